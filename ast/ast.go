@@ -99,6 +99,47 @@ func (cs *ConstStatement) String() string {
 	return out.String()
 }
 
+type PublicStatement struct {
+	Token token.Token
+	Name  *Identifier
+	Value Expression
+}
+
+func (ps *PublicStatement) statementNode() {}
+
+func (ps *PublicStatement) TokenLiteral() string {
+	return ps.Token.Literal
+}
+
+func (ps *PublicStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ps.TokenLiteral() + " ")
+	out.WriteString(ps.Name.String())
+	out.WriteString(" = ")
+
+	if ps.Value != nil {
+		out.WriteString(ps.Value.String())
+	}
+
+	return out.String()
+}
+
+type OptionStatement struct {
+	Token token.Token
+	Name  *Identifier // EXPLICITなど
+}
+
+func (opt *OptionStatement) statementNode() {}
+
+func (opt *OptionStatement) TokenLiteral() string {
+	return opt.Token.Literal
+}
+
+func (opt *OptionStatement) String() string {
+	return opt.TokenLiteral() + " " + opt.Name.String()
+}
+
 type ExpressionStatement struct {
 	Token token.Token // 式の最初のトークン
 	Expression
@@ -243,10 +284,13 @@ func (rs *ResultStatement) String() string {
 	return rs.Token.Literal
 }
 
+// HASHTBL 変数 = [オプション], [キー, 値, ...]
 type HashTableStatement struct {
-	Token token.Token
-	Name  *Identifier
-	Value Expression
+	Token  token.Token
+	Name   *Identifier
+	Value  Expression   // オプション。省略した場合はEmpty
+	Keys   []Expression // 初期値のキー
+	Values []Expression // Keysと同じ順番
 }
 
 func (hts *HashTableStatement) statementNode() {}
@@ -260,9 +304,14 @@ func (hts *HashTableStatement) String() string {
 	out.WriteString(hts.Name.String())
 	out.WriteString(" = ")
 
-	if hts.Value != nil {
-		out.WriteString(hts.Value.String())
+	items := []string{}
+	if _, ok := hts.Value.(*Empty); !ok && hts.Value != nil {
+		items = append(items, hts.Value.String())
+	}
+	for i, key := range hts.Keys {
+		items = append(items, key.String(), hts.Values[i].String())
 	}
+	out.WriteString(strings.Join(items, ", "))
 
 	return out.String()
 }
@@ -437,6 +486,18 @@ func (b *Boolean) String() string {
 	return b.Token.Literal
 }
 
+type Null struct {
+	Token token.Token
+}
+
+func (n *Null) expressionNode() {}
+func (n *Null) TokenLiteral() string {
+	return n.Token.Literal
+}
+func (n *Null) String() string {
+	return n.Token.Literal
+}
+
 type AssignmentExpression struct {
 	Token token.Token
 	Left  Expression
@@ -544,6 +605,27 @@ func (ie *IndexExpression) String() string {
 	return out.String()
 }
 
+type DotExpression struct {
+	Token  token.Token // '.' トークン
+	Left   Expression
+	Member *Identifier
+}
+
+func (de *DotExpression) expressionNode() {}
+func (de *DotExpression) TokenLiteral() string {
+	return de.Token.Literal
+}
+func (de *DotExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(de.Left.String())
+	out.WriteString(".")
+	out.WriteString(de.Member.String())
+	out.WriteString(")")
+	return out.String()
+}
+
 type EmptyArgument struct {
 	Token token.Token
 }
@@ -24,6 +24,10 @@ var builtinFunctions = map[string]*object.BuiltinFunction{
 				return &object.BuiltinFuncReturnResult{
 					Value: &object.Integer{Value: int64(len(arg.Elements))},
 				}
+			case *object.HashTable:
+				return &object.BuiltinFuncReturnResult{
+					Value: &object.Integer{Value: int64(arg.Len())},
+				}
 			default:
 				return &object.BuiltinFuncReturnResult{
 					Value: newError("argument to `LENGTH` not supported, got %s", args[0].Value.Type()),
@@ -71,98 +75,69 @@ var builtinFunctions = map[string]*object.BuiltinFunction{
 			return newError("wrong number of arguments. got=%d, want=1", len(args))
 		},
 	},
+	// CALCARRAY(配列, 計算方法, [開始], [終了])
+	// NOTE: EMPTYや数値に変換できない要素は計算から除く
 	"CALCARRAY": {
 		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
-			if len(args) >= 2 {
-				array, ok := args[0].Value.(*object.Array)
-				if !ok {
-					return newError("argument 1 to `CALCARRAY` not supported, got %s", args[0].Value.Type())
+			if len(args) < 2 || len(args) > 4 {
+				return wrongNumberOfArguments("CALCARRAY", len(args), "2 to 4")
+			}
+			array, ok := args[0].Value.(*object.Array)
+			if !ok {
+				return newError("argument 1 to `CALCARRAY` not supported, got %s", args[0].Value.Type())
+			}
+			cons, ok := args[1].Value.(*object.BuiltinConstant)
+			if !ok {
+				return newError("argument 2 to `CALCARRAY` not supported, got %s", args[1].Value.Type())
+			}
+			from, err := integerArgument("CALCARRAY", args, 2, 0)
+			if err != nil {
+				return err
+			}
+			to, err := integerArgument("CALCARRAY", args, 3, int64(len(array.Elements)-1))
+			if err != nil {
+				return err
+			}
+			values := []object.Object{}
+			// NOTE: 一致しなかったMATCHの結果のような空の配列は範囲を確認しない
+			if len(array.Elements) > 0 {
+				if from < 0 || from >= int64(len(array.Elements)) {
+					return newError("index out of range in `CALCARRAY`. from=%d, length=%d", from, len(array.Elements))
 				}
-				cons, ok := args[1].Value.(*object.BuiltinConstant)
-				if !ok {
-					return newError("argument 2 to `CALCARRAY` not supported, got %s", args[1].Value.Type())
+				if to < from || to >= int64(len(array.Elements)) {
+					return newError("index out of range in `CALCARRAY`. to=%d, length=%d", to, len(array.Elements))
 				}
-				from := int64(0)
-				to := int64(len(array.Elements) - 1)
-				if len(args) >= 3 {
-					fromArg, ok := args[2].Value.(*object.Integer)
-					if ok {
-						from = fromArg.Value
-					} else {
-						_, ok := args[2].Value.(*object.Empty)
-						if ok {
-							from = 0
-						} else {
-							return newError("argument 3 to `CALCARRAY` not supported, got %s", args[2].Value.Type())
-						}
-					}
-					if len(args) == 4 {
-						toArg, ok := args[3].Value.(*object.Integer)
-						if !ok {
-							return newError("argument 4 to `CALCARRAY` not supported, got %s", args[3].Value.Type())
-						}
-						to = toArg.Value
+				for _, e := range array.Elements[from : to+1] {
+					if num, ok := toNumber(e); ok {
+						values = append(values, num)
 					}
 				}
+			}
 
-				if cons.T == CALC_ADD {
-					var sum int64
-					for i := from; i <= to; i++ {
-						v, ok := array.Elements[i].(*object.Integer)
-						if !ok {
-							return newError("array of argument 1 has not integer element. array[%d]=%q", i, v)
-						}
-						sum += v.Value
-					}
-					return &object.BuiltinFuncReturnResult{
-						Value: &object.Integer{
-							Value: sum,
-						},
-					}
+			// NOTE: 計算する数値がない場合、CALC_ADDとCALC_AVRは0、CALC_MINとCALC_MAXはEMPTYを返す
+			switch cons.T {
+			case CALC_ADD:
+				return returnResult(sumNumbers(values))
+			case CALC_MIN, CALC_MAX:
+				if len(values) == 0 {
+					return returnResult(EMPTY)
 				}
-				if cons.T == CALC_MIN {
-					var min int64
-					for i := from; i <= to; i++ {
-						v, ok := array.Elements[i].(*object.Integer)
-						if !ok {
-							return newError("array of argument 1 has not integer element. array[%d]=%q", i, v)
-						}
-						if i == 0 {
-							min = v.Value
-						}
-						if v.Value < min {
-							min = v.Value
-						}
-					}
-					return &object.BuiltinFuncReturnResult{
-						Value: &object.Integer{
-							Value: min,
-						},
+				result := values[0]
+				for _, v := range values[1:] {
+					if cons.T == CALC_MIN && toFloat(v) < toFloat(result) ||
+						cons.T == CALC_MAX && toFloat(v) > toFloat(result) {
+						result = v
 					}
 				}
-				if cons.T == CALC_MAX {
-					var max int64
-					for i := from; i <= to; i++ {
-						v, ok := array.Elements[i].(*object.Integer)
-						if !ok {
-							return newError("array of argument 1 has not integer element. array[%d]=%q", i, v)
-						}
-						if i == 0 {
-							max = v.Value
-						}
-						if v.Value > max {
-							max = v.Value
-						}
-					}
-					return &object.BuiltinFuncReturnResult{
-						Value: &object.Integer{
-							Value: max,
-						},
-					}
+				return returnResult(result)
+			case CALC_AVR:
+				if len(values) == 0 {
+					return returnResult(&object.Integer{Value: 0})
 				}
+				return returnResult(newNumber(toFloat(sumNumbers(values)) / float64(len(values))))
+			default:
+				return newError("argument 2 to `CALCARRAY` not supported, got %s", cons.T)
 			}
-			// TODO: CALC_AVRによる平均値を求める処理
-			return newError("wrong number of arguments. got=%d, want=1", len(args))
 		},
 	},
 }
@@ -250,6 +225,125 @@ var builtinConstants = map[object.BuiltinConstantType]object.Object{
 	},
 }
 
+// 機能ごとのファイルに定義した組み込み関数と組み込み定数を登録する
+func init() {
+	for _, functions := range []map[string]*object.BuiltinFunction{
+		stringBuiltinFunctions,
+		splitBuiltinFunctions,
+		formatBuiltinFunctions,
+		conversionBuiltinFunctions,
+		mathBuiltinFunctions,
+		arrayBuiltinFunctions,
+		regexBuiltinFunctions,
+		fileBuiltinFunctions,
+		filesystemBuiltinFunctions,
+		iniBuiltinFunctions,
+		csvBuiltinFunctions,
+		jsonBuiltinFunctions,
+		datetimeBuiltinFunctions,
+		processBuiltinFunctions,
+	} {
+		for name, fn := range functions {
+			builtinFunctions[name] = fn
+		}
+	}
+	for _, constants := range []map[object.BuiltinConstantType]object.Object{
+		stringBuiltinConstants,
+		conversionBuiltinConstants,
+		arrayBuiltinConstants,
+		fileBuiltinConstants,
+		filesystemBuiltinConstants,
+	} {
+		for t, cons := range constants {
+			builtinConstants[t] = cons
+		}
+	}
+}
+
+func newBuiltinConstant(t object.BuiltinConstantType, value int64) *object.BuiltinConstant {
+	return &object.BuiltinConstant{
+		T:     t,
+		Value: &object.Integer{Value: value},
+	}
+}
+
+func returnResult(value object.Object) object.Object {
+	return &object.BuiltinFuncReturnResult{Value: value}
+}
+
+func wrongNumberOfArguments(name string, got int, want string) *object.Error {
+	return newError("wrong number of arguments to `%s`. got=%d, want=%s", name, got, want)
+}
+
+// 省略(EMPTY)された引数か
+func isOmitted(args []object.BuiltinFuncArgument, i int) bool {
+	return i >= len(args) || args[i].Value == EMPTY
+}
+
+func stringArgument(name string, args []object.BuiltinFuncArgument, i int) (string, *object.Error) {
+	switch arg := args[i].Value.(type) {
+	case *object.String:
+		return arg.Value, nil
+	case *object.Integer, *object.Float:
+		// NOTE: UWSCでは数値も文字列として扱える
+		return arg.Inspect(), nil
+	default:
+		return "", newError("argument %d to `%s` not supported, got %s", i+1, name, args[i].Value.Type())
+	}
+}
+
+// 省略された場合はdefaultValueを返す
+func integerArgument(name string, args []object.BuiltinFuncArgument, i int, defaultValue int64) (int64, *object.Error) {
+	if isOmitted(args, i) {
+		return defaultValue, nil
+	}
+	switch arg := args[i].Value.(type) {
+	case *object.Integer:
+		return arg.Value, nil
+	case *object.Float:
+		return int64(arg.Value), nil
+	case *object.Boolean:
+		if arg.Value {
+			return 1, nil
+		}
+		return 0, nil
+	case *object.BuiltinConstant:
+		if v, ok := arg.Value.(*object.Integer); ok {
+			return v.Value, nil
+		}
+	}
+	return 0, newError("argument %d to `%s` not supported, got %s", i+1, name, args[i].Value.Type())
+}
+
+// 省略された場合はdefaultValueを返す
+func boolArgument(name string, args []object.BuiltinFuncArgument, i int, defaultValue bool) (bool, *object.Error) {
+	if isOmitted(args, i) {
+		return defaultValue, nil
+	}
+	v, err := integerArgument(name, args, i, 0)
+	if err != nil {
+		return false, err
+	}
+	return v != 0, nil
+}
+
+func builtinConstantValue(t object.BuiltinConstantType) int64 {
+	return builtinConstants[t].(*object.BuiltinConstant).Value.(*object.Integer).Value
+}
+
+// 全て整数の場合は整数で合計し、桁あふれする場合は実数で計算する
+func sumNumbers(values []object.Object) object.Object {
+	var sum object.Object = &object.Integer{Value: 0}
+	for _, v := range values {
+		if sum.Type() == object.INTEGER_OBJ && v.Type() == object.INTEGER_OBJ {
+			sum = evalIntegerInfixExpression("+", sum, v)
+		} else {
+			sum = evalFloatInfixExpression("+", sum, v)
+		}
+	}
+	return sum
+}
+
 func builtin(key string) (object.Object, bool) {
 	k := strings.ToUpper(key)
 	if result, ok := builtinConstants[object.BuiltinConstantType(k)]; ok {
@@ -0,0 +1,394 @@
+package evaluator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+var arrayBuiltinFunctions = map[string]*object.BuiltinFunction{
+	// SLICE(配列, [開始], [終了]) 指定した範囲の要素を新しい配列で返す
+	"SLICE": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 3 {
+				return wrongNumberOfArguments("SLICE", len(args), "1 to 3")
+			}
+			array, err := arrayArgument("SLICE", args, 0)
+			if err != nil {
+				return err
+			}
+			from, err := integerArgument("SLICE", args, 1, 0)
+			if err != nil {
+				return err
+			}
+			to, err := integerArgument("SLICE", args, 2, int64(len(array.Elements)-1))
+			if err != nil {
+				return err
+			}
+			if from < 0 {
+				from = 0
+			}
+			if to > int64(len(array.Elements)-1) {
+				to = int64(len(array.Elements) - 1)
+			}
+			elements := []object.Object{}
+			if from <= to {
+				elements = append(elements, array.Elements[from:to+1]...)
+			}
+			return returnResult(&object.Array{Elements: elements})
+		},
+	},
+	// QSORT(var 配列, [順序], [var 連動配列...])
+	// 連動配列は配列と同じ順に並べ替え、配列の値が等しい場合は連動配列の値で並べる
+	"QSORT": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 10 {
+				return wrongNumberOfArguments("QSORT", len(args), "1 to 10")
+			}
+			primary, err := arrayArgument("QSORT", args, 0)
+			if err != nil {
+				return err
+			}
+			arrays := []*object.Array{primary}
+			for i := 2; i < len(args); i++ {
+				array, err := arrayArgument("QSORT", args, i)
+				if err != nil {
+					return err
+				}
+				if len(array.Elements) < len(primary.Elements) {
+					return newError("argument %d to `QSORT` should have at least %d elements, got %d", i+1, len(primary.Elements), len(array.Elements))
+				}
+				arrays = append(arrays, array)
+			}
+			order, err := integerArgument("QSORT", args, 1, builtinConstantValue(QSRT_A))
+			if err != nil {
+				return err
+			}
+			var compare func(a, b object.Object) int
+			descending := false
+			switch order {
+			case builtinConstantValue(QSRT_A), builtinConstantValue(QSRT_D):
+				compare = compareFold
+				descending = order == builtinConstantValue(QSRT_D)
+			case builtinConstantValue(QSRT_UNICODEA), builtinConstantValue(QSRT_UNICODED):
+				compare = compareUnicode
+				descending = order == builtinConstantValue(QSRT_UNICODED)
+			case builtinConstantValue(QSRT_NATURALA), builtinConstantValue(QSRT_NATURALD):
+				compare = compareNatural
+				descending = order == builtinConstantValue(QSRT_NATURALD)
+			default:
+				return newError("argument 2 to `QSORT` not supported, got %d", order)
+			}
+
+			indexes := indexesFrom(0, len(primary.Elements))
+			sort.SliceStable(indexes, func(i, j int) bool {
+				for _, array := range arrays {
+					c := compare(array.Elements[indexes[i]], array.Elements[indexes[j]])
+					if c != 0 {
+						return (c < 0) != descending
+					}
+				}
+				return false
+			})
+
+			refs := []*object.BuiltinFuncReturnReference{}
+			for n, array := range arrays {
+				sorted := make([]object.Object, len(array.Elements))
+				copy(sorted, array.Elements)
+				for i, idx := range indexes {
+					sorted[i] = array.Elements[idx]
+				}
+				argIndex := 0
+				if n > 0 {
+					argIndex = n + 1
+				}
+				refs = append(refs, &object.BuiltinFuncReturnReference{
+					Expression: args[argIndex].Expression,
+					Value:      &object.Array{Elements: sorted},
+				})
+			}
+			return &object.BuiltinFuncReturnReferences{References: refs, Result: NULL}
+		},
+	},
+	// REVERSE(var 配列) 要素を逆順にする
+	"REVERSE": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 1 {
+				return wrongNumberOfArguments("REVERSE", len(args), "1")
+			}
+			array, err := arrayArgument("REVERSE", args, 0)
+			if err != nil {
+				return err
+			}
+			reversed := make([]object.Object, len(array.Elements))
+			for i, e := range array.Elements {
+				reversed[len(array.Elements)-1-i] = e
+			}
+			return &object.BuiltinFuncReturnReference{
+				Expression: args[0].Expression,
+				Value:      &object.Array{Elements: reversed},
+				Result:     NULL,
+			}
+		},
+	},
+	// SETCLEAR(var 配列, [値]) 全ての要素を値(省略時はEMPTY)にする
+	"SETCLEAR": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return wrongNumberOfArguments("SETCLEAR", len(args), "1 or 2")
+			}
+			array, err := arrayArgument("SETCLEAR", args, 0)
+			if err != nil {
+				return err
+			}
+			var value object.Object = EMPTY
+			if len(args) == 2 {
+				value = args[1].Value
+			}
+			cleared := make([]object.Object, len(array.Elements))
+			for i := range cleared {
+				cleared[i] = value
+			}
+			return &object.BuiltinFuncReturnReference{
+				Expression: args[0].Expression,
+				Value:      &object.Array{Elements: cleared},
+				Result:     NULL,
+			}
+		},
+	},
+	// SHIFTARRAY(var 配列, シフト数) プラスは後ろへ、マイナスは前へずらし、空いた要素はEMPTYにする
+	"SHIFTARRAY": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 2 {
+				return wrongNumberOfArguments("SHIFTARRAY", len(args), "2")
+			}
+			array, err := arrayArgument("SHIFTARRAY", args, 0)
+			if err != nil {
+				return err
+			}
+			shift, err := integerArgument("SHIFTARRAY", args, 1, 0)
+			if err != nil {
+				return err
+			}
+			shifted := make([]object.Object, len(array.Elements))
+			for i := range shifted {
+				from := int64(i) - shift
+				if from < 0 || from >= int64(len(array.Elements)) {
+					shifted[i] = EMPTY
+				} else {
+					shifted[i] = array.Elements[from]
+				}
+			}
+			return &object.BuiltinFuncReturnReference{
+				Expression: args[0].Expression,
+				Value:      &object.Array{Elements: shifted},
+				Result:     NULL,
+			}
+		},
+	},
+	// ARRAYFILTER(配列, 検索文字列, [一致方法], [除外]) 一致する要素を新しい配列で返す
+	// 除外がTRUEの場合は一致しない要素を返す
+	"ARRAYFILTER": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 2 || len(args) > 4 {
+				return wrongNumberOfArguments("ARRAYFILTER", len(args), "2 to 4")
+			}
+			array, err := arrayArgument("ARRAYFILTER", args, 0)
+			if err != nil {
+				return err
+			}
+			search, err := stringArgument("ARRAYFILTER", args, 1)
+			if err != nil {
+				return err
+			}
+			mode, err := integerArgument("ARRAYFILTER", args, 2, builtinConstantValue(AF_CONTAIN))
+			if err != nil {
+				return err
+			}
+			exclude, err := boolArgument("ARRAYFILTER", args, 3, false)
+			if err != nil {
+				return err
+			}
+			var match func(s, substr string) bool
+			switch mode {
+			case builtinConstantValue(AF_CONTAIN):
+				match = strings.Contains
+			case builtinConstantValue(AF_EXACT):
+				match = func(s, substr string) bool { return s == substr }
+			case builtinConstantValue(AF_PREFIX):
+				match = strings.HasPrefix
+			case builtinConstantValue(AF_SUFFIX):
+				match = strings.HasSuffix
+			default:
+				return newError("argument 3 to `ARRAYFILTER` not supported, got %d", mode)
+			}
+
+			elements := []object.Object{}
+			for _, e := range array.Elements {
+				// NOTE: 文字列の比較と同様に大文字小文字を区別しない
+				matched := match(strings.ToLower(toStringValue(e)), strings.ToLower(search))
+				if matched != exclude {
+					elements = append(elements, e)
+				}
+			}
+			return returnResult(&object.Array{Elements: elements})
+		},
+	},
+}
+
+const (
+	QSRT_A        = object.BuiltinConstantType("QSRT_A")
+	QSRT_D        = object.BuiltinConstantType("QSRT_D")
+	QSRT_UNICODEA = object.BuiltinConstantType("QSRT_UNICODEA")
+	QSRT_UNICODED = object.BuiltinConstantType("QSRT_UNICODED")
+	QSRT_NATURALA = object.BuiltinConstantType("QSRT_NATURALA")
+	QSRT_NATURALD = object.BuiltinConstantType("QSRT_NATURALD")
+	AF_CONTAIN    = object.BuiltinConstantType("AF_CONTAIN")
+	AF_EXACT      = object.BuiltinConstantType("AF_EXACT")
+	AF_PREFIX     = object.BuiltinConstantType("AF_PREFIX")
+	AF_SUFFIX     = object.BuiltinConstantType("AF_SUFFIX")
+)
+
+var arrayBuiltinConstants = map[object.BuiltinConstantType]object.Object{
+	QSRT_A:        newBuiltinConstant(QSRT_A, 0),
+	QSRT_D:        newBuiltinConstant(QSRT_D, 1),
+	QSRT_UNICODEA: newBuiltinConstant(QSRT_UNICODEA, 2),
+	QSRT_UNICODED: newBuiltinConstant(QSRT_UNICODED, 3),
+	QSRT_NATURALA: newBuiltinConstant(QSRT_NATURALA, 4),
+	QSRT_NATURALD: newBuiltinConstant(QSRT_NATURALD, 5),
+	AF_CONTAIN:    newBuiltinConstant(AF_CONTAIN, 0),
+	AF_EXACT:      newBuiltinConstant(AF_EXACT, 1),
+	AF_PREFIX:     newBuiltinConstant(AF_PREFIX, 2),
+	AF_SUFFIX:     newBuiltinConstant(AF_SUFFIX, 3),
+}
+
+func arrayArgument(name string, args []object.BuiltinFuncArgument, i int) (*object.Array, *object.Error) {
+	array, ok := args[i].Value.(*object.Array)
+	if !ok {
+		return nil, newError("argument %d to `%s` not supported, got %s", i+1, name, args[i].Value.Type())
+	}
+	return array, nil
+}
+
+// from以上to未満の整数を順に並べて返す
+func indexesFrom(from, to int) []int {
+	indexes := []int{}
+	for i := from; i < to; i++ {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// 数値は文字列より前に並べる。EMPTYは空文字として扱う
+func compareValues(a, b object.Object, compareStrings func(a, b string) int) int {
+	aNum, aIsNum := toNumberOrNil(a)
+	bNum, bIsNum := toNumberOrNil(b)
+	switch {
+	case aIsNum && bIsNum:
+		switch {
+		case aNum < bNum:
+			return -1
+		case aNum > bNum:
+			return 1
+		default:
+			return 0
+		}
+	case aIsNum:
+		return -1
+	case bIsNum:
+		return 1
+	default:
+		return compareStrings(toStringValue(a), toStringValue(b))
+	}
+}
+
+func toNumberOrNil(obj object.Object) (float64, bool) {
+	if !isNumber(obj) {
+		return 0, false
+	}
+	return toFloat(obj), true
+}
+
+// 大文字小文字を区別せずに比較する
+func compareFold(a, b object.Object) int {
+	return compareValues(a, b, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+}
+
+// 文字コード順に比較する
+func compareUnicode(a, b object.Object) int {
+	return compareValues(a, b, strings.Compare)
+}
+
+// 文字列中の数字を数値として比較する(自然順)
+func compareNatural(a, b object.Object) int {
+	return compareValues(a, b, naturalCompare)
+}
+
+// NOTE: 全角の数字は半角の数字と同じ数値として比べる
+func naturalCompare(a, b string) int {
+	ar, br := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	i, j := 0, 0
+	for i < len(ar) && j < len(br) {
+		_, aDigit := asciiDigit(ar[i])
+		_, bDigit := asciiDigit(br[j])
+		if aDigit && bDigit {
+			var an, bn string
+			an, i = readDigits(ar, i)
+			bn, j = readDigits(br, j)
+			if len(an) != len(bn) {
+				if len(an) < len(bn) {
+					return -1
+				}
+				return 1
+			}
+			if c := strings.Compare(an, bn); c != 0 {
+				return c
+			}
+			continue
+		}
+		if ar[i] != br[j] {
+			if ar[i] < br[j] {
+				return -1
+			}
+			return 1
+		}
+		i++
+		j++
+	}
+	switch {
+	case len(ar)-i < len(br)-j:
+		return -1
+	case len(ar)-i > len(br)-j:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// 0から9の数字を半角にして返す。数字でない場合はfalseを返す
+func asciiDigit(r rune) (rune, bool) {
+	switch {
+	case '0' <= r && r <= '9':
+		return r, true
+	case '０' <= r && r <= '９':
+		return r - '０' + '0', true
+	default:
+		return 0, false
+	}
+}
+
+// i文字目から続く数字を、先頭の0を除いた半角の数字にして返す
+func readDigits(runes []rune, i int) (string, int) {
+	var digits strings.Builder
+	for ; i < len(runes); i++ {
+		d, ok := asciiDigit(runes[i])
+		if !ok {
+			break
+		}
+		digits.WriteRune(d)
+	}
+	return strings.TrimLeft(digits.String(), "0"), i
+}
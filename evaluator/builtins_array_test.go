@@ -0,0 +1,197 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+func TestArrayBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{
+			"SLICE_開始から最後までの要素を返す",
+			`DIM array[] = 1, 2, 3, 4
+JOIN(SLICE(array, 2), ",")`,
+			"3,4",
+		},
+		{
+			"SLICE_開始と終了の範囲の要素を返す",
+			`DIM array[] = 1, 2, 3, 4
+JOIN(SLICE(array, 1, 2), ",")`,
+			"2,3",
+		},
+		{
+			"SLICE_範囲外の場合は空の配列を返す",
+			`DIM array[] = 1, 2
+LENGTH(SLICE(array, 5))`,
+			0,
+		},
+		{
+			"SLICE_元の配列は変更しない",
+			`DIM array[] = 1, 2, 3
+SLICE(array, 1)
+LENGTH(array)`,
+			3,
+		},
+		{
+			"QSORT_昇順に並べ替える",
+			`DIM array[] = 3, 1, 2
+QSORT(array)
+JOIN(array, ",")`,
+			"1,2,3",
+		},
+		{
+			"QSORT_降順に並べ替える",
+			`DIM array[] = 3, 1, 2
+QSORT(array, QSRT_D)
+JOIN(array, ",")`,
+			"3,2,1",
+		},
+		{
+			"QSORT_文字列は大文字小文字を区別せずに並べ替える",
+			`DIM array[] = "b", "C", "a"
+QSORT(array, QSRT_A)
+JOIN(array, ",")`,
+			"a,b,C",
+		},
+		{
+			"QSORT_QSRT_UNICODEAは文字コード順に並べ替える",
+			`DIM array[] = "b", "C", "a"
+QSORT(array, QSRT_UNICODEA)
+JOIN(array, ",")`,
+			"C,a,b",
+		},
+		{
+			"QSORT_数値は文字列より前に並べる",
+			`DIM array[] = "a", 2, 1
+QSORT(array)
+JOIN(array, ",")`,
+			"1,2,a",
+		},
+		{
+			"QSORT_QSRT_NATURALAは数字を数値として並べ替える",
+			`DIM array[] = "file10", "file2", "file1"
+QSORT(array, QSRT_NATURALA)
+JOIN(array, ",")`,
+			"file1,file2,file10",
+		},
+		{
+			"QSORT_QSRT_NATURALAは全角の数字も数値として並べ替える",
+			`DIM array[] = "file１０", "file２", "file1"
+QSORT(array, QSRT_NATURALA)
+JOIN(array, ",")`,
+			"file1,file２,file１０",
+		},
+		{
+			"QSORT_QSRT_NATURALDは自然順の降順に並べ替える",
+			`DIM array[] = "file10", "file2", "file1"
+QSORT(array, QSRT_NATURALD)
+JOIN(array, ",")`,
+			"file10,file2,file1",
+		},
+		{
+			"QSORT_連動配列を同じ順に並べ替える",
+			`DIM keys[] = 3, 1, 2
+DIM values[] = "c", "a", "b"
+QSORT(keys, QSRT_A, values)
+JOIN(values, ",")`,
+			"a,b,c",
+		},
+		{
+			"QSORT_値が等しい場合は連動配列の値で並べ替える",
+			`DIM keys[] = 2, 1, 2, 1
+DIM values[] = "d", "b", "c", "a"
+DIM others[] = 1, 2, 3, 4
+QSORT(keys, QSRT_A, values, others)
+JOIN(values, ",") + ":" + JOIN(others, ",")`,
+			"a,b,c,d:4,2,3,1",
+		},
+		{
+			"QSORT_連動配列の要素数が足りない場合はエラーになる",
+			`DIM keys[] = 3, 1, 2
+DIM values[] = "c"
+QSORT(keys, QSRT_A, values)`,
+			&object.Error{Message: "argument 3 to `QSORT` should have at least 3 elements, got 1"},
+		},
+		{
+			"REVERSE_要素を逆順にする",
+			`DIM array[] = 1, 2, 3
+REVERSE(array)
+JOIN(array, ",")`,
+			"3,2,1",
+		},
+		{
+			"SETCLEAR_全ての要素をEMPTYにする",
+			`DIM array[] = 1, 2
+SETCLEAR(array)
+EMPTY = array[1]`,
+			true,
+		},
+		{
+			"SETCLEAR_全ての要素を指定した値にする",
+			`DIM array[] = 1, 2, 3
+SETCLEAR(array, 0)
+JOIN(array, ",")`,
+			"0,0,0",
+		},
+		{
+			"SHIFTARRAY_プラスの場合は後ろへずらす",
+			`DIM array[] = 1, 2, 3, 4
+SHIFTARRAY(array, 2)
+JOIN(array, ",")`,
+			",,1,2",
+		},
+		{
+			"SHIFTARRAY_マイナスの場合は前へずらす",
+			`DIM array[] = 1, 2, 3, 4
+SHIFTARRAY(array, -1)
+JOIN(array, ",")`,
+			"2,3,4,",
+		},
+		{
+			"ARRAYFILTER_部分一致する要素を返す",
+			`DIM array[] = "apple", "banana", "grape"
+JOIN(ARRAYFILTER(array, "ap"), ",")`,
+			"apple,grape",
+		},
+		{
+			"ARRAYFILTER_大文字小文字を区別しない",
+			`DIM array[] = "Apple", "banana"
+JOIN(ARRAYFILTER(array, "APPLE", AF_EXACT), ",")`,
+			"Apple",
+		},
+		{
+			"ARRAYFILTER_前方一致する要素を返す",
+			`DIM array[] = "apple", "grape"
+JOIN(ARRAYFILTER(array, "ap", AF_PREFIX), ",")`,
+			"apple",
+		},
+		{
+			"ARRAYFILTER_後方一致する要素を返す",
+			`DIM array[] = "apple", "grape"
+JOIN(ARRAYFILTER(array, "pe", AF_SUFFIX), ",")`,
+			"grape",
+		},
+		{
+			"ARRAYFILTER_除外がTRUEの場合は一致しない要素を返す",
+			`DIM array[] = "apple", "banana", "grape"
+JOIN(ARRAYFILTER(array, "ap", AF_CONTAIN, TRUE), ",")`,
+			"banana",
+		},
+		{
+			"REVERSE_配列以外はエラーになる",
+			`REVERSE(1)`,
+			&object.Error{Message: "argument 1 to `REVERSE` not supported, got INTEGER"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testExpectedObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
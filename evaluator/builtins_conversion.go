@@ -0,0 +1,232 @@
+package evaluator
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+var conversionBuiltinFunctions = map[string]*object.BuiltinFunction{
+	// VAL(文字列, [エラー値]) 数値に変換できない場合はエラー値を返す
+	"VAL": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return wrongNumberOfArguments("VAL", len(args), "1 or 2")
+			}
+			if num, ok := toNumber(args[0].Value); ok {
+				return returnResult(num)
+			}
+			if isOmitted(args, 1) {
+				return returnResult(&object.Integer{Value: VAL_ERROR_DEFAULT})
+			}
+			return returnResult(args[1].Value)
+		},
+	},
+	// CHKNUM(値) 数値として扱えるか
+	"CHKNUM": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 1 {
+				return wrongNumberOfArguments("CHKNUM", len(args), "1")
+			}
+			_, ok := toNumber(args[0].Value)
+			return returnResult(nativeBoolToBooleanObject(ok))
+		},
+	},
+	// VARTYPE(値, [変換する型]) 型を省略した場合は値の型を返す
+	"VARTYPE": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return wrongNumberOfArguments("VARTYPE", len(args), "1 or 2")
+			}
+			if isOmitted(args, 1) {
+				return returnResult(&object.Integer{Value: varType(args[0].Value)})
+			}
+			t, err := integerArgument("VARTYPE", args, 1, 0)
+			if err != nil {
+				return err
+			}
+			return returnResult(convertVarType(args[0].Value, t))
+		},
+	},
+	// TYPEOF(値) 値の型名を返す
+	// Number(整数と実数), String, Bool, Empty, Null, Array, HashTbl, File, Function のいずれか
+	"TYPEOF": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 1 {
+				return wrongNumberOfArguments("TYPEOF", len(args), "1")
+			}
+			value := args[0].Value
+			if c, ok := value.(*object.BuiltinConstant); ok {
+				value = c.Value
+			}
+			name, ok := typeNames[value.Type()]
+			if !ok {
+				return newError("argument 1 to `TYPEOF` not supported, got %s", value.Type())
+			}
+			return returnResult(&object.String{Value: name})
+		},
+	},
+}
+
+// TYPEOFが返す型名。内部の型名はスクリプトに見せない
+var typeNames = map[object.ObjectType]string{
+	object.INTEGER_OBJ:          "Number",
+	object.FLOAT_OBJ:            "Number",
+	object.STRING_OBJ:           "String",
+	object.BOOLEAN_OBJ:          "Bool",
+	object.EMPTY_OBJ:            "Empty",
+	object.NULL_OBJ:             "Null",
+	object.ARRAY_OBJ:            "Array",
+	object.HASHTBL_OBJ:          "HashTbl",
+	object.FILE_OBJ:             "File",
+	object.FUNCTION_OBJ:         "Function",
+	object.BUILTIN_FUNCTION_OBJ: "Function",
+}
+
+// VALで変換できない場合のデフォルト値
+const VAL_ERROR_DEFAULT = -999999
+
+const (
+	VAR_EMPTY   = object.BuiltinConstantType("VAR_EMPTY")
+	VAR_NULL    = object.BuiltinConstantType("VAR_NULL")
+	VAR_INTEGER = object.BuiltinConstantType("VAR_INTEGER")
+	VAR_DOUBLE  = object.BuiltinConstantType("VAR_DOUBLE")
+	VAR_BSTR    = object.BuiltinConstantType("VAR_BSTR")
+	VAR_BOOLEAN = object.BuiltinConstantType("VAR_BOOLEAN")
+	VAR_VARIANT = object.BuiltinConstantType("VAR_VARIANT")
+	VAR_UNKNOWN = object.BuiltinConstantType("VAR_UNKNOWN")
+	VAR_INT64   = object.BuiltinConstantType("VAR_INT64")
+	VAR_ASTR    = object.BuiltinConstantType("VAR_ASTR")
+	VAR_USTR    = object.BuiltinConstantType("VAR_USTR")
+	VAR_HASHTBL = object.BuiltinConstantType("VAR_HASHTBL")
+	VAR_ARRAY   = object.BuiltinConstantType("VAR_ARRAY")
+)
+
+var conversionBuiltinConstants = map[object.BuiltinConstantType]object.Object{
+	VAR_EMPTY:   newBuiltinConstant(VAR_EMPTY, 0),
+	VAR_NULL:    newBuiltinConstant(VAR_NULL, 1),
+	VAR_INTEGER: newBuiltinConstant(VAR_INTEGER, 3),
+	VAR_DOUBLE:  newBuiltinConstant(VAR_DOUBLE, 5),
+	VAR_BSTR:    newBuiltinConstant(VAR_BSTR, 8),
+	VAR_BOOLEAN: newBuiltinConstant(VAR_BOOLEAN, 11),
+	VAR_VARIANT: newBuiltinConstant(VAR_VARIANT, 12),
+	VAR_UNKNOWN: newBuiltinConstant(VAR_UNKNOWN, 13),
+	VAR_INT64:   newBuiltinConstant(VAR_INT64, 20),
+	VAR_ASTR:    newBuiltinConstant(VAR_ASTR, 256),
+	VAR_USTR:    newBuiltinConstant(VAR_USTR, 258),
+	// NOTE: UWSCに対応する値がないため独自の値とする
+	VAR_HASHTBL: newBuiltinConstant(VAR_HASHTBL, 512),
+	VAR_ARRAY:   newBuiltinConstant(VAR_ARRAY, 8192),
+}
+
+// UWSCの数値は全て倍精度実数なので、整数もVAR_DOUBLEとして扱う
+func varType(obj object.Object) int64 {
+	switch obj := obj.(type) {
+	case *object.Empty:
+		return builtinConstantValue(VAR_EMPTY)
+	case *object.Null:
+		return builtinConstantValue(VAR_NULL)
+	case *object.Integer, *object.Float:
+		return builtinConstantValue(VAR_DOUBLE)
+	case *object.String:
+		return builtinConstantValue(VAR_USTR)
+	case *object.Boolean:
+		return builtinConstantValue(VAR_BOOLEAN)
+	case *object.Array:
+		return builtinConstantValue(VAR_ARRAY) | builtinConstantValue(VAR_VARIANT)
+	case *object.HashTable:
+		return builtinConstantValue(VAR_HASHTBL)
+	case *object.BuiltinConstant:
+		return varType(obj.Value)
+	default:
+		return builtinConstantValue(VAR_UNKNOWN)
+	}
+}
+
+func convertVarType(obj object.Object, t int64) object.Object {
+	if cons, ok := obj.(*object.BuiltinConstant); ok {
+		obj = cons.Value
+	}
+	switch t {
+	case builtinConstantValue(VAR_EMPTY):
+		return EMPTY
+	case builtinConstantValue(VAR_NULL):
+		return NULL
+	case builtinConstantValue(VAR_DOUBLE):
+		num, ok := toNumber(obj)
+		if !ok {
+			return newError("cannot convert %s to VAR_DOUBLE: %s", obj.Type(), obj.Inspect())
+		}
+		return num
+	case builtinConstantValue(VAR_INTEGER), builtinConstantValue(VAR_INT64):
+		num, ok := toNumber(obj)
+		if !ok {
+			return newError("cannot convert %s to VAR_INTEGER: %s", obj.Type(), obj.Inspect())
+		}
+		return &object.Integer{Value: int64(toFloat(num))}
+	case builtinConstantValue(VAR_BSTR), builtinConstantValue(VAR_ASTR), builtinConstantValue(VAR_USTR):
+		return &object.String{Value: toStringValue(obj)}
+	case builtinConstantValue(VAR_BOOLEAN):
+		if num, ok := toNumber(obj); ok {
+			return nativeBoolToBooleanObject(toFloat(num) != 0)
+		}
+		return nativeBoolToBooleanObject(isTruthy(obj))
+	default:
+		return newError("argument 2 to `VARTYPE` not supported, got %d", t)
+	}
+}
+
+// 数値または数値として解釈できる文字列を数値に変換する
+func toNumber(obj object.Object) (object.Object, bool) {
+	switch obj := obj.(type) {
+	case *object.Integer, *object.Float:
+		return obj, true
+	case *object.String:
+		return parseNumber(obj.Value)
+	case *object.BuiltinConstant:
+		return toNumber(obj.Value)
+	default:
+		return nil, false
+	}
+}
+
+// 文字列として連結する時の値
+func toStringValue(obj object.Object) string {
+	if obj == EMPTY {
+		return ""
+	}
+	return obj.Inspect()
+}
+
+// 数値として解釈できる文字列をIntegerかFloatに変換する
+// NOTE: 先頭が$または&Hの場合は16進数として扱う
+func parseNumber(str string) (object.Object, bool) {
+	s := strings.TrimSpace(str)
+	upper := strings.ToUpper(s)
+	for _, prefix := range []string{"$", "&H", "0X"} {
+		if strings.HasPrefix(upper, prefix) {
+			i, err := strconv.ParseUint(s[len(prefix):], 16, 64)
+			if err != nil {
+				return nil, false
+			}
+			return &object.Integer{Value: int64(i)}, true
+		}
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return &object.Integer{Value: i}, true
+	}
+	// NOTE: InfやNaNなどは数値として扱わない
+	if strings.IndexFunc(s, isNotDecimalRune) >= 0 {
+		return nil, false
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return &object.Float{Value: f}, true
+	}
+	return nil, false
+}
+
+// 10進数の数値(指数表記を含む)に使えない文字の場合はtrueを返す
+func isNotDecimalRune(r rune) bool {
+	return r != '.' && r != '-' && r != '+' && r != 'e' && r != 'E' && (r < '0' || r > '9')
+}
@@ -0,0 +1,68 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+func TestConversionBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"VAL_文字列を整数に変換する", `VAL("123")`, 123},
+		{"VAL_前後の空白を無視する", `VAL(" -12 ")`, -12},
+		{"VAL_小数の文字列を実数に変換する", `VAL("1.25")`, 1.25},
+		{"VAL_$から始まる文字列は16進数として変換する", `VAL("$FF")`, 255},
+		{"VAL_&Hから始まる文字列は16進数として変換する", `VAL("&h10")`, 16},
+		{"VAL_変換できない場合は-999999を返す", `VAL("abc")`, -999999},
+		{"VAL_変換できない場合は指定したエラー値を返す", `VAL("abc", 0)`, 0},
+		{"VAL_数値はそのまま返す", `VAL(5)`, 5},
+		{"CHKNUM_数値の場合はTRUEを返す", `CHKNUM(1)`, true},
+		{"CHKNUM_数値に変換できる文字列の場合はTRUEを返す", `CHKNUM("1.5")`, true},
+		{"CHKNUM_数値に変換できない文字列の場合はFALSEを返す", `CHKNUM("1a")`, false},
+		{"CHKNUM_InfやNaNは数値として扱わない", `CHKNUM("NaN")`, false},
+		{"CHKNUM_真偽値の場合はFALSEを返す", `CHKNUM(TRUE)`, false},
+		{"VARTYPE_数値の場合はVAR_DOUBLEを返す", `VARTYPE(1)`, 5},
+		{"VARTYPE_文字列の場合はVAR_USTRを返す", `VARTYPE("a")`, 258},
+		{"VARTYPE_真偽値の場合はVAR_BOOLEANを返す", `VARTYPE(TRUE)`, 11},
+		{"VARTYPE_EMPTYの場合はVAR_EMPTYを返す", "DIM array[1]\nVARTYPE(array[0])", 0},
+		{
+			"VARTYPE_配列の場合はVAR_ARRAYとVAR_VARIANTを返す",
+			`DIM array[] = 1, 2
+VARTYPE(array)`,
+			8204,
+		},
+		{
+			"VARTYPE_連想配列の場合はVAR_HASHTBLを返す",
+			`HASHTBL hash
+VARTYPE(hash)`,
+			512,
+		},
+		{"VARTYPE_VAR_DOUBLEを指定すると数値に変換する", `VARTYPE("12", VAR_DOUBLE) + 1`, 13},
+		{"VARTYPE_VAR_INTEGERを指定すると整数に変換する", `VARTYPE("3.7", VAR_INTEGER)`, 3},
+		{"VARTYPE_VAR_USTRを指定すると文字列に変換する", `VARTYPE(12, VAR_USTR) + 1`, "121"},
+		{"VARTYPE_VAR_BOOLEANを指定すると真偽値に変換する", `VARTYPE("0", VAR_BOOLEAN)`, false},
+		{"VARTYPE_変換できない場合はエラーになる", `VARTYPE("a", VAR_DOUBLE)`, &object.Error{Message: "cannot convert STRING to VAR_DOUBLE: a"}},
+		{"TYPEOF_整数はNumberを返す", `TYPEOF(1)`, "Number"},
+		{"TYPEOF_実数はNumberを返す", `TYPEOF(VAL("1.5"))`, "Number"},
+		{"TYPEOF_組み込み定数は値の型名を返す", `TYPEOF(HASH_SORT)`, "Number"},
+		{"TYPEOF_文字列はStringを返す", `TYPEOF("a")`, "String"},
+		{"TYPEOF_真偽値はBoolを返す", `TYPEOF(TRUE)`, "Bool"},
+		{"TYPEOF_EMPTYはEmptyを返す", `TYPEOF(EMPTY)`, "Empty"},
+		{"TYPEOF_NULLはNullを返す", `TYPEOF(NULL)`, "Null"},
+		{"TYPEOF_配列はArrayを返す", `DIM a[] = 1
+TYPEOF(a)`, "Array"},
+		{"TYPEOF_連想配列はHashTblを返す", `HASHTBL h
+TYPEOF(h)`, "HashTbl"},
+		{"TYPEOF_組み込み関数はFunctionを返す", `TYPEOF(LENGTH)`, "Function"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testExpectedObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
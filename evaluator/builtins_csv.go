@@ -0,0 +1,89 @@
+package evaluator
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+var csvBuiltinFunctions = map[string]*object.BuiltinFunction{
+	// CSVPARSE(文字列, [区切り文字=","])
+	// 各行を配列にした二次元配列を返す。ダブルクォート内の区切り文字や改行、""によるエスケープに対応する
+	"CSVPARSE": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return wrongNumberOfArguments("CSVPARSE", len(args), "1 or 2")
+			}
+			text, err := stringArgument("CSVPARSE", args, 0)
+			if err != nil {
+				return err
+			}
+			sep, err := csvSeparatorArgument("CSVPARSE", args, 1)
+			if err != nil {
+				return err
+			}
+			records, parseErr := parseCSV(text, sep)
+			if parseErr != nil {
+				return newError("failed to parse csv in `CSVPARSE`: %s", parseErr)
+			}
+			rows := []object.Object{}
+			for _, record := range records {
+				rows = append(rows, stringsToArray(record))
+			}
+			return returnResult(&object.Array{Elements: rows})
+		},
+	},
+	// CSVFORMAT(二次元配列, [区切り文字=","])
+	// 必要な値だけダブルクォートで囲み、行をCRLFでつないだ文字列を返す
+	// NOTE: 値の中の改行もCRLFにそろえる
+	"CSVFORMAT": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return wrongNumberOfArguments("CSVFORMAT", len(args), "1 or 2")
+			}
+			array, err := arrayArgument("CSVFORMAT", args, 0)
+			if err != nil {
+				return err
+			}
+			sep, err := csvSeparatorArgument("CSVFORMAT", args, 1)
+			if err != nil {
+				return err
+			}
+			records := [][]string{}
+			for _, row := range array.Elements {
+				// NOTE: 配列でない要素は1列だけの行として扱う
+				columns, ok := row.(*object.Array)
+				if !ok {
+					records = append(records, []string{toStringValue(row)})
+					continue
+				}
+				record := []string{}
+				for _, column := range columns.Elements {
+					record = append(record, toStringValue(column))
+				}
+				records = append(records, record)
+			}
+			text, formatErr := formatCSV(records, sep, "\r\n")
+			if formatErr != nil {
+				return newError("failed to format csv in `CSVFORMAT`: %s", formatErr)
+			}
+			return returnResult(&object.String{Value: strings.TrimSuffix(text, "\r\n")})
+		},
+	},
+}
+
+func csvSeparatorArgument(name string, args []object.BuiltinFuncArgument, i int) (rune, *object.Error) {
+	if isOmitted(args, i) {
+		return ',', nil
+	}
+	sep, err := stringArgument(name, args, i)
+	if err != nil {
+		return 0, err
+	}
+	if utf8.RuneCountInString(sep) != 1 || sep == `"` || sep == "\r" || sep == "\n" {
+		return 0, newError("invalid separator %q in `%s`", sep, name)
+	}
+	r, _ := utf8.DecodeRuneInString(sep)
+	return r, nil
+}
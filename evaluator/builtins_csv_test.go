@@ -0,0 +1,38 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+const testCSV = "name,comment\r\n" +
+	"\"Smith, John\",\"say \"\"hi\"\"\"\r\n" +
+	"multi,\"line1\r\nline2\"\r\n"
+
+func TestCSVBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"CSVPARSE_行数を返す", `LENGTH(CSVPARSE(text))`, 3},
+		{"CSVPARSE_ダブルクォート内のカンマを含めて返す", `CSVPARSE(text)[1][0]`, "Smith, John"},
+		{"CSVPARSE_連続したダブルクォートを1つにする", `CSVPARSE(text)[1][1]`, "say \"hi\""},
+		{"CSVPARSE_ダブルクォート内の改行を含めて返す", `CSVPARSE(text)[2][1]`, "line1\nline2"},
+		{"CSVPARSE_区切り文字を指定できる", `CSVPARSE("a;b", ";")[0][1]`, "b"},
+		{"CSVPARSE_区切り文字が1文字でない場合はエラーになる", `CSVPARSE("a", ";;")`, &object.Error{Message: "invalid separator \";;\" in `CSVPARSE`"}},
+		{"CSVFORMAT_必要な値だけダブルクォートで囲む", `CSVFORMAT(CSVPARSE(text))`, "name,comment\r\n\"Smith, John\",\"say \"\"hi\"\"\"\r\nmulti,\"line1\r\nline2\""},
+		{"CSVFORMAT_配列でない要素は1列の行として扱う", "DIM rows[] = \"a\", \"b\"\nCSVFORMAT(rows)", "a\r\nb"},
+		{"CSVFORMAT_区切り文字を指定できる", "DIM a[] = \"a\", \"b c\"\nDIM b[] = 1, 2\nDIM rows[] = a, b\nCSVFORMAT(rows, \" \")", "a \"b c\"\r\n1 2"},
+		{"CSVFORMAT_配列以外はエラーになる", `CSVFORMAT("a")`, &object.Error{Message: "argument 1 to `CSVFORMAT` not supported, got STRING"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, []byte(testCSV))
+			input := "f = FOPEN(\"" + path + "\", F_READ)\ntext = FGET(f, F_ALLTEXT)\n" + tt.input
+			testExpectedObject(t, testEval(input), tt.expected)
+		})
+	}
+}
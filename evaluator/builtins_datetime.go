@@ -0,0 +1,212 @@
+package evaluator
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+const defaultDateTimeFormat = "yyyy/mm/dd hh:nn:ss"
+
+// GETTICKCOUNTが返す経過時間の基準
+var tickOrigin = time.Now()
+
+var datetimeBuiltinFunctions = map[string]*object.BuiltinFunction{
+	// GETTIME([日数=0], [基準日時], [秒数=0])
+	// 2000/01/01 00:00:00からの秒数を返し、G_TIME_YY/MM/DD/HH/NN/SS/ZZ/WWに日時の各要素を設定する
+	// NOTE: 基準日時を省略した場合は現在時刻を基準にする。G_TIME_WWは日曜日を0とする曜日
+	"GETTIME": {
+		EnvFn: func(env *object.Environment, args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) > 3 {
+				return wrongNumberOfArguments("GETTIME", len(args), "0 to 3")
+			}
+			days, err := integerArgument("GETTIME", args, 0, 0)
+			if err != nil {
+				return err
+			}
+			t := env.Now()
+			if !isOmitted(args, 1) {
+				if s, ok := args[1].Value.(*object.String); !ok || s.Value != "" {
+					t, err = dateArgument("GETTIME", args, 1)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			seconds, err := integerArgument("GETTIME", args, 2, 0)
+			if err != nil {
+				return err
+			}
+			t = t.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second)
+			for _, g := range timeGlobals(t) {
+				env.SetPublic(g.name, g.value)
+			}
+			return returnResult(&object.Integer{Value: getTimeFromTime(t)})
+		},
+	},
+	// SLEEP(秒) 小数で1秒未満も指定できる
+	// NOTE: 待機中にコンテキストがキャンセルされた場合はすぐにスクリプトを中断する
+	"SLEEP": {
+		EnvFn: func(env *object.Environment, args ...object.BuiltinFuncArgument) object.Object {
+			ctx := env.Context()
+			if len(args) != 1 {
+				return wrongNumberOfArguments("SLEEP", len(args), "1")
+			}
+			num, err := numberArgument("SLEEP", args, 0)
+			if err != nil {
+				return err
+			}
+			seconds := toFloat(num)
+			if seconds <= 0 {
+				return returnResult(NULL)
+			}
+			d := time.Duration(math.MaxInt64)
+			if seconds < float64(math.MaxInt64)/float64(time.Second) {
+				d = time.Duration(seconds * float64(time.Second))
+			}
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				return returnResult(NULL)
+			case <-ctx.Done():
+				return interruptedError(ctx.Err())
+			}
+		},
+	},
+	// GETTICKCOUNT() 経過時間をミリ秒で返す。処理時間の計測に使う
+	"GETTICKCOUNT": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 0 {
+				return wrongNumberOfArguments("GETTICKCOUNT", len(args), "0")
+			}
+			return returnResult(&object.Integer{Value: time.Since(tickOrigin).Milliseconds()})
+		},
+	},
+	// DATESTR(GETTIMEの値, [書式="yyyy/mm/dd hh:nn:ss"])
+	"DATESTR": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return wrongNumberOfArguments("DATESTR", len(args), "1 or 2")
+			}
+			seconds, err := integerArgument("DATESTR", args, 0, 0)
+			if err != nil {
+				return err
+			}
+			layout := defaultDateTimeFormat
+			if !isOmitted(args, 1) {
+				if layout, err = stringArgument("DATESTR", args, 1); err != nil {
+					return err
+				}
+			}
+			t := timeFromGetTime(float64(seconds))
+			return returnResult(&object.String{Value: formatDateTime(t, layout)})
+		},
+	},
+	// DATEADD(単位, 数, 日時)
+	// 単位は yyyy(年) m(月) d(日) ww(週) h(時) n(分) s(秒)。結果は yyyy/mm/dd hh:nn:ss 形式の文字列
+	// NOTE: 日時は文字列またはGETTIMEの値で指定する。DATEDIFFとFORMATも同じ
+	"DATEADD": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 3 {
+				return wrongNumberOfArguments("DATEADD", len(args), "3")
+			}
+			interval, err := stringArgument("DATEADD", args, 0)
+			if err != nil {
+				return err
+			}
+			n, err := integerArgument("DATEADD", args, 1, 0)
+			if err != nil {
+				return err
+			}
+			t, err := dateArgument("DATEADD", args, 2)
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(interval) {
+			case "yyyy":
+				t = addMonths(t, int(n)*12)
+			case "m":
+				t = addMonths(t, int(n))
+			case "d":
+				t = t.AddDate(0, 0, int(n))
+			case "ww":
+				t = t.AddDate(0, 0, int(n)*7)
+			case "h":
+				t = t.Add(time.Duration(n) * time.Hour)
+			case "n":
+				t = t.Add(time.Duration(n) * time.Minute)
+			case "s":
+				t = t.Add(time.Duration(n) * time.Second)
+			default:
+				return newError("unknown interval %q in `DATEADD`", interval)
+			}
+			return returnResult(&object.String{Value: formatDateTime(t, defaultDateTimeFormat)})
+		},
+	},
+	// DATEDIFF(単位, 日時1, 日時2)
+	// 日時1から日時2までに単位の境界をいくつ越えるかを返す。単位はDATEADDと同じ
+	"DATEDIFF": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 3 {
+				return wrongNumberOfArguments("DATEDIFF", len(args), "3")
+			}
+			interval, err := stringArgument("DATEDIFF", args, 0)
+			if err != nil {
+				return err
+			}
+			from, err := dateArgument("DATEDIFF", args, 1)
+			if err != nil {
+				return err
+			}
+			to, err := dateArgument("DATEDIFF", args, 2)
+			if err != nil {
+				return err
+			}
+			from, to = wallClock(from), wallClock(to)
+			var diff int64
+			switch strings.ToLower(interval) {
+			case "yyyy":
+				diff = int64(to.Year() - from.Year())
+			case "m":
+				diff = int64((to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month()))
+			case "d":
+				diff = int64(to.Truncate(24*time.Hour).Sub(from.Truncate(24*time.Hour)) / (24 * time.Hour))
+			case "ww":
+				// NOTE: 日曜日を週の始まりとする
+				fromWeek := from.Truncate(24*time.Hour).AddDate(0, 0, -int(from.Weekday()))
+				toWeek := to.Truncate(24*time.Hour).AddDate(0, 0, -int(to.Weekday()))
+				diff = int64(toWeek.Sub(fromWeek) / (7 * 24 * time.Hour))
+			case "h":
+				diff = int64(to.Truncate(time.Hour).Sub(from.Truncate(time.Hour)) / time.Hour)
+			case "n":
+				diff = int64(to.Truncate(time.Minute).Sub(from.Truncate(time.Minute)) / time.Minute)
+			case "s":
+				diff = to.Unix() - from.Unix()
+			default:
+				return newError("unknown interval %q in `DATEDIFF`", interval)
+			}
+			return returnResult(&object.Integer{Value: diff})
+		},
+	},
+}
+
+// 日時を表す文字列またはGETTIMEの値の引数を返す
+func dateArgument(name string, args []object.BuiltinFuncArgument, i int) (time.Time, *object.Error) {
+	switch v := args[i].Value.(type) {
+	case *object.String:
+		t, ok := parseDateTime(v.Value)
+		if !ok {
+			return time.Time{}, newError("cannot parse %q as date in `%s`", v.Value, name)
+		}
+		return t, nil
+	case *object.Integer:
+		return timeFromGetTime(float64(v.Value)), nil
+	case *object.Float:
+		return timeFromGetTime(v.Value), nil
+	default:
+		return time.Time{}, newError("argument %d to `%s` not supported, got %s", i+1, name, args[i].Value.Type())
+	}
+}
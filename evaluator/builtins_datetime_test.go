@@ -0,0 +1,122 @@
+package evaluator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+func TestDateTimeBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"GETTIME_2000年1月1日からの秒数を返す", `GETTIME(0, "2000/01/02 00:00:01")`, 86401},
+		{"GETTIME_現在時刻の秒数を返す", `DATESTR(GETTIME())`, "2021/03/04 05:06:07"},
+		{"GETTIME_日数を加算する", `DATESTR(GETTIME(-4))`, "2021/02/28 05:06:07"},
+		{"GETTIME_秒数を加算する", `DATESTR(GETTIME(0, "2021/12/31 23:59:59", 1))`, "2022/01/01 00:00:00"},
+		{"GETTIME_基準日時に空文字を指定すると現在時刻を基準にする", `DATESTR(GETTIME(1, ""))`, "2021/03/05 05:06:07"},
+		{"GETTIME_G_TIMEに年月日を設定する", `GETTIME()
+G_TIME_YY * 10000 + G_TIME_MM * 100 + G_TIME_DD`, 20210304},
+		{"GETTIME_G_TIMEに時分秒とミリ秒を設定する", `GETTIME()
+G_TIME_HH * 10000 + G_TIME_NN * 100 + G_TIME_SS + G_TIME_ZZ`, 51497},
+		{"GETTIME_G_TIME_WWは日曜日を0とする", `GETTIME(3)
+G_TIME_WW`, 0},
+		{"GETTIME_関数の中で呼び出してもG_TIMEを参照できる", `PROCEDURE update()
+	GETTIME(0, "1999/12/31")
+FEND
+update()
+G_TIME_YY`, 1999},
+		{"GETTIME_日時として解釈できない場合はエラーになる", `GETTIME(0, "abc")`, &object.Error{Message: "cannot parse \"abc\" as date in `GETTIME`"}},
+		{"DATESTR_書式を指定する", `DATESTR(GETTIME(), "yyyy年mm月dd日(aaa)")`, "2021年03月04日(木)"},
+		{"DATEADD_日数を加算する", `DATEADD("d", 30, "2021/03/04")`, "2021/04/03 00:00:00"},
+		{"DATEADD_月末を超える場合は月末にそろえる", `DATEADD("m", 1, "2021/01/31 12:00:00")`, "2021/02/28 12:00:00"},
+		{"DATEADD_うるう日の1年後は2月28日", `DATEADD("yyyy", 1, "2020/02/29")`, "2021/02/28 00:00:00"},
+		{"DATEADD_週を減算する", `DATEADD("ww", -1, "2021/03/04")`, "2021/02/25 00:00:00"},
+		{"DATEADD_時間を加算する", `DATEADD("h", 20, "2021/03/04 05:00:00")`, "2021/03/05 01:00:00"},
+		{"DATEADD_GETTIMEの値を指定できる", `DATEADD("d", 1, GETTIME())`, "2021/03/05 05:06:07"},
+		{"DATEDIFF_GETTIMEの値を指定できる", `DATEDIFF("d", GETTIME(), "2021/03/14")`, 10},
+		{"DATESTR_GETTIMEを呼ぶ前でもG_TIMEを参照できる", `G_TIME_YY * 10000 + G_TIME_MM * 100 + G_TIME_DD`, 20210304},
+		{"DATEADD_不明な単位はエラーになる", `DATEADD("x", 1, "2021/03/04")`, &object.Error{Message: "unknown interval \"x\" in `DATEADD`"}},
+		{"DATEDIFF_日の境界の数を返す", `DATEDIFF("d", "2021/03/04 23:00:00", "2021/03/05 01:00:00")`, 1},
+		{"DATEDIFF_過去の日時は負の値になる", `DATEDIFF("d", "2021/03/04", "2021/02/04")`, -28},
+		{"DATEDIFF_月の差を返す", `DATEDIFF("m", "2020/12/31", "2021/01/01")`, 1},
+		{"DATEDIFF_年の差を返す", `DATEDIFF("yyyy", "2020/01/01", "2021/12/31")`, 1},
+		{"DATEDIFF_週の差は日曜日を境界とする", `DATEDIFF("ww", "2021/03/06", "2021/03/07")`, 1},
+		{"DATEDIFF_秒の差を返す", `DATEDIFF("s", "2021/03/04 00:00:00", "2021/03/04 01:00:01")`, 3601},
+		{"DATEDIFF_不明な単位はエラーになる", `DATEDIFF("q", "2021/03/04", "2021/03/04")`, &object.Error{Message: "unknown interval \"q\" in `DATEDIFF`"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := object.NewEnvironment()
+			// NOTE: 2021/03/04(木) 05:06:07.890 に固定する
+			env.SetClock(func() time.Time {
+				return time.Date(2021, 3, 4, 5, 6, 7, 890*int(time.Millisecond), time.Local)
+			})
+			testExpectedObject(t, testEvalEnv(env, tt.input), tt.expected)
+		})
+	}
+}
+
+func TestSleep(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"SLEEP_小数の秒数だけ待機する", `start = GETTICKCOUNT()
+SLEEP(VAL("0.05"))
+GETTICKCOUNT() - start >= 50`, true},
+		{"SLEEP_0以下の場合は待機しない", `start = GETTICKCOUNT()
+SLEEP(-1)
+GETTICKCOUNT() - start < 1000`, true},
+		{"SLEEP_数値以外はエラーになる", `SLEEP("abc")`, &object.Error{Message: "argument 1 to `SLEEP` not supported, got STRING"}},
+		{"GETTICKCOUNT_引数を指定するとエラーになる", `GETTICKCOUNT(1)`, &object.Error{Message: "wrong number of arguments to `GETTICKCOUNT`. got=1, want=0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testExpectedObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
+
+func testEvalContext(ctx context.Context, input string) object.Object {
+	env := object.NewEnvironment()
+	env.SetContext(ctx)
+	return testEvalEnv(env, input)
+}
+
+func TestCancel(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"SLEEP_キャンセルされると待機を中断する", `SLEEP(10)`},
+		{"FOR_キャンセルされると繰り返しを中断する", `FOR i = 0 TO 100000000
+	SLEEP(VAL("0.001"))
+NEXT`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			start := time.Now()
+			testExpectedObject(t, testEvalContext(ctx, tt.input), &object.Error{Message: "script interrupted: context deadline exceeded"})
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("script was not interrupted. elapsed=%s", elapsed)
+			}
+		})
+	}
+}
+
+func TestCancel_キャンセル済みの場合は実行しない(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	testExpectedObject(t, testEvalContext(ctx, "DIM val = 1\nval"), &object.Error{Message: "script interrupted: context canceled"})
+}
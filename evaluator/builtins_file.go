@@ -0,0 +1,336 @@
+package evaluator
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+var fileBuiltinFunctions = map[string]*object.BuiltinFunction{
+	// FOPEN(ファイル名, モード) F_EXISTSの場合はファイルが存在するかを返す
+	// NOTE: FCLOSEしなかったファイルはスクリプトの終了時にCloseFilesで書き込む
+	"FOPEN": {
+		EnvFn: func(env *object.Environment, args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return wrongNumberOfArguments("FOPEN", len(args), "1 or 2")
+			}
+			path, err := stringArgument("FOPEN", args, 0)
+			if err != nil {
+				return err
+			}
+			mode, err := integerArgument("FOPEN", args, 1, builtinConstantValue(F_READ))
+			if err != nil {
+				return err
+			}
+			if mode&builtinConstantValue(F_EXISTS) != 0 {
+				info, statErr := os.Stat(path)
+				return returnResult(nativeBoolToBooleanObject(statErr == nil && !info.IsDir()))
+			}
+			opened := openFile(path, mode)
+			if file, ok := opened.(*object.File); ok {
+				env.AddOpenFile(file)
+			}
+			return returnResult(opened)
+		},
+	},
+	// FGET(ファイル, 行, [列], [ダブルクォートを除去=TRUE])
+	// 行にF_LINECOUNTを指定すると行数、F_ALLTEXTを指定すると全文を返す
+	// NOTE: 列を指定した場合は行から始まるCSVのレコードとして解釈し、ダブルクォートの中の改行は次の行も含める
+	// 第4引数がFALSEの場合は値を囲むダブルクォートと""をファイルに書かれたまま返す
+	"FGET": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 2 || len(args) > 4 {
+				return wrongNumberOfArguments("FGET", len(args), "2 to 4")
+			}
+			file, err := fileArgument("FGET", args, 0)
+			if err != nil {
+				return err
+			}
+			if !file.Readable {
+				return newError("file is not opened for reading: %s", file.Path)
+			}
+			row, err := integerArgument("FGET", args, 1, 0)
+			if err != nil {
+				return err
+			}
+			column, err := integerArgument("FGET", args, 2, 0)
+			if err != nil {
+				return err
+			}
+			unquote, err := boolArgument("FGET", args, 3, true)
+			if err != nil {
+				return err
+			}
+			switch row {
+			case builtinConstantValue(F_LINECOUNT):
+				return returnResult(&object.Integer{Value: int64(file.LineCount())})
+			case builtinConstantValue(F_ALLTEXT):
+				return returnResult(&object.String{Value: strings.Join(file.Lines(), file.Newline)})
+			}
+			line, ok := file.Line(int(row))
+			if !ok {
+				return returnResult(EMPTY)
+			}
+			if column == 0 {
+				return returnResult(&object.String{Value: line})
+			}
+			record, _ := csvRecord(file.Lines(), int(row)-1)
+			columns := splitRawColumns(record, file.Tab)
+			if column < 1 || column > int64(len(columns)) {
+				return returnResult(EMPTY)
+			}
+			if !unquote {
+				return returnResult(&object.String{Value: columns[column-1]})
+			}
+			return returnResult(&object.String{Value: unquoteColumn(columns[column-1])})
+		},
+	},
+	// FPUT(ファイル, 値, [行], [列])
+	// 行が0の場合は最後に追加し、F_ALLTEXTの場合は全文を置き換える
+	// 列にF_INSERTを指定すると行の前に挿入する
+	// NOTE: 列を指定して書き込む場合、複数行にまたがるCSVのレコードと改行を含む値には対応しない
+	"FPUT": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 2 || len(args) > 4 {
+				return wrongNumberOfArguments("FPUT", len(args), "2 to 4")
+			}
+			file, err := fileArgument("FPUT", args, 0)
+			if err != nil {
+				return err
+			}
+			if !file.Writable {
+				return newError("file is not opened for writing: %s", file.Path)
+			}
+			value := toStringValue(args[1].Value)
+			row, err := integerArgument("FPUT", args, 2, 0)
+			if err != nil {
+				return err
+			}
+			column, err := integerArgument("FPUT", args, 3, 0)
+			if err != nil {
+				return err
+			}
+			switch {
+			case row == builtinConstantValue(F_ALLTEXT):
+				file.SetLines(splitLines(value))
+			case row == 0 || file.Append:
+				file.AppendLine(value)
+			case row < 0:
+				return newError("argument 3 to `FPUT` not supported, got %d", row)
+			case column == builtinConstantValue(F_INSERT):
+				file.InsertLine(int(row), value)
+			case column == 0:
+				file.SetLine(int(row), value)
+			case column > 0:
+				if strings.ContainsAny(value, "\r\n") {
+					return newError("value with a newline cannot be written to a column in `FPUT`")
+				}
+				line, _ := file.Line(int(row))
+				if strings.Count(line, `"`)%2 == 1 {
+					return newError("multi-line csv record is not supported in `FPUT`: line %d", row)
+				}
+				columns := splitColumns(line, file.Tab)
+				for int64(len(columns)) < column {
+					columns = append(columns, "")
+				}
+				columns[column-1] = value
+				file.SetLine(int(row), joinColumns(columns, file.Tab))
+			default:
+				return newError("argument 4 to `FPUT` not supported, got %d", column)
+			}
+			return returnResult(NULL)
+		},
+	},
+	// FDELLINE(ファイル, 行)
+	"FDELLINE": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 2 {
+				return wrongNumberOfArguments("FDELLINE", len(args), "2")
+			}
+			file, err := fileArgument("FDELLINE", args, 0)
+			if err != nil {
+				return err
+			}
+			if !file.Writable || file.Append {
+				return newError("file is not opened for writing: %s", file.Path)
+			}
+			row, err := integerArgument("FDELLINE", args, 1, 0)
+			if err != nil {
+				return err
+			}
+			return returnResult(nativeBoolToBooleanObject(file.DeleteLine(int(row))))
+		},
+	},
+	// FCLOSE(ファイル, [書き込まない]) 変更がある場合はファイルに書き込む
+	"FCLOSE": {
+		EnvFn: func(env *object.Environment, args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return wrongNumberOfArguments("FCLOSE", len(args), "1 or 2")
+			}
+			file, err := fileArgument("FCLOSE", args, 0)
+			if err != nil {
+				return err
+			}
+			discard, err := boolArgument("FCLOSE", args, 1, false)
+			if err != nil {
+				return err
+			}
+			file.Closed = true
+			env.RemoveOpenFile(file)
+			if discard || !file.Modified {
+				return returnResult(TRUE)
+			}
+			if err := writeFile(file); err != nil {
+				return newError("cannot write file: %s", err)
+			}
+			return returnResult(TRUE)
+		},
+	},
+}
+
+const (
+	F_EXISTS    = object.BuiltinConstantType("F_EXISTS")
+	F_READ      = object.BuiltinConstantType("F_READ")
+	F_WRITE     = object.BuiltinConstantType("F_WRITE")
+	F_NOCR      = object.BuiltinConstantType("F_NOCR")
+	F_TAB       = object.BuiltinConstantType("F_TAB")
+	F_APPEND    = object.BuiltinConstantType("F_APPEND")
+	F_LINECOUNT = object.BuiltinConstantType("F_LINECOUNT")
+	F_ALLTEXT   = object.BuiltinConstantType("F_ALLTEXT")
+	F_INSERT    = object.BuiltinConstantType("F_INSERT")
+)
+
+var fileBuiltinConstants = map[object.BuiltinConstantType]object.Object{
+	F_EXISTS:    newBuiltinConstant(F_EXISTS, 1),
+	F_READ:      newBuiltinConstant(F_READ, 2),
+	F_WRITE:     newBuiltinConstant(F_WRITE, 4),
+	F_NOCR:      newBuiltinConstant(F_NOCR, 128),
+	F_TAB:       newBuiltinConstant(F_TAB, 256),
+	F_APPEND:    newBuiltinConstant(F_APPEND, 1024),
+	F_LINECOUNT: newBuiltinConstant(F_LINECOUNT, -1),
+	F_ALLTEXT:   newBuiltinConstant(F_ALLTEXT, -2),
+	F_INSERT:    newBuiltinConstant(F_INSERT, -1),
+}
+
+// F_WRITEのみの場合は空のファイルとして開き、F_READを含む場合は既存の内容を読み込む
+func openFile(path string, mode int64) object.Object {
+	file := object.NewFile(path, []string{})
+	file.Encoding = ENCODING_UTF8
+	file.Append = mode&builtinConstantValue(F_APPEND) != 0
+	// NOTE: F_APPENDの場合は既存の内容を読み込まないので読み込みはできない
+	file.Readable = mode&builtinConstantValue(F_READ) != 0 && !file.Append
+	file.Writable = mode&(builtinConstantValue(F_WRITE)|builtinConstantValue(F_APPEND)) != 0
+	file.NoCR = mode&builtinConstantValue(F_NOCR) != 0
+	file.Tab = mode&builtinConstantValue(F_TAB) != 0
+	if !file.Readable && !file.Writable {
+		return newError("argument 2 to `FOPEN` not supported, got %d", mode)
+	}
+	if file.Writable && !file.Readable && !file.Append {
+		// NOTE: 上書きの場合は閉じた時に空のファイルになるようにする
+		file.Modified = true
+		return file
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && file.Writable {
+			file.Modified = true
+			return file
+		}
+		return newError("cannot open file: %s", err)
+	}
+	text, enc, err := decodeText(b)
+	if err != nil {
+		return newError("cannot decode file as %s: %s", enc, path)
+	}
+	file.Encoding = enc
+	if strings.Contains(text, "\r\n") || !strings.Contains(text, "\n") {
+		file.Newline = "\r\n"
+	} else {
+		file.Newline = "\n"
+	}
+	if !file.Append {
+		file.SetLines(splitLines(text))
+		file.Modified = false
+	}
+	return file
+}
+
+// FCLOSEしていないファイルを閉じて変更を書き込む。スクリプトの終了時に呼ぶ
+// NOTE: 書き込めなかったファイルがあっても残りのファイルは書き込み、最初のエラーを返す
+func CloseFiles(env *object.Environment) error {
+	var firstErr error
+	for _, file := range env.OpenFiles() {
+		file.Closed = true
+		env.RemoveOpenFile(file)
+		if !file.Modified {
+			continue
+		}
+		if err := writeFile(file); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func writeFile(file *object.File) error {
+	text := strings.Join(file.Lines(), file.Newline)
+	if file.LineCount() > 0 && !file.NoCR {
+		text += file.Newline
+	}
+	if file.Append {
+		return appendFile(file, text)
+	}
+	b, err := encodeText(text, file.Encoding)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file.Path, b, 0644)
+}
+
+// 既存の内容の後ろに追加する。既存の内容が改行で終わっていない場合は改行を補う
+func appendFile(file *object.File, text string) error {
+	existing, err := os.ReadFile(file.Path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	prefix := ""
+	if len(existing) > 0 {
+		decoded, _, err := decodeText(existing)
+		if err != nil {
+			return err
+		}
+		prefix = decoded
+		if !strings.HasSuffix(prefix, "\n") {
+			prefix += file.Newline
+		}
+	}
+	b, err := encodeText(prefix+text, file.Encoding)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file.Path, b, 0644)
+}
+
+// 改行(CRLFまたはLF)で分割する。末尾の改行は行として数えない
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	text = strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	return strings.Split(text, "\n")
+}
+
+func fileArgument(name string, args []object.BuiltinFuncArgument, i int) (*object.File, *object.Error) {
+	file, ok := args[i].Value.(*object.File)
+	if !ok {
+		return nil, newError("argument %d to `%s` not supported, got %s", i+1, name, args[i].Value.Type())
+	}
+	if file.Closed {
+		return nil, newError("file is already closed: %s", file.Path)
+	}
+	return file, nil
+}
@@ -0,0 +1,260 @@
+package evaluator_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sam8helloworld/uwscgo/object"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func writeTestFile(t *testing.T, content []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestFileRead(t *testing.T) {
+	sjis, _ := japanese.ShiftJIS.NewEncoder().Bytes([]byte("あいう\r\nえお\r\n"))
+	utf16, _ := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte("あいう\r\nえお\r\n"))
+	tests := []struct {
+		name     string
+		content  []byte
+		input    string
+		expected interface{}
+	}{
+		{"FGET_指定した行を返す", []byte("a\r\nb\r\nc\r\n"), `FGET(f, 2)`, "b"},
+		{"FGET_LFの改行も扱える", []byte("a\nb\n"), `FGET(f, 2)`, "b"},
+		{"FGET_存在しない行はEMPTYを返す", []byte("a\r\n"), `EMPTY = FGET(f, 3)`, true},
+		{"FGET_F_LINECOUNTで行数を返す", []byte("a\r\nb\r\nc"), `FGET(f, F_LINECOUNT)`, 3},
+		{"FGET_F_ALLTEXTで全文を返す", []byte("a\r\nb\r\n"), `FGET(f, F_ALLTEXT)`, "a\r\nb"},
+		{"FGET_列を指定するとカンマで区切った値を返す", []byte("a,b,c\r\n"), `FGET(f, 1, 2)`, "b"},
+		{"FGET_ダブルクォートを除去する", []byte("a,\"b\"\r\n"), `FGET(f, 1, 2, TRUE)`, "b"},
+		{"FGET_ダブルクォート内のカンマは区切りとして扱わない", []byte("a,\"b,c\",d\r\n"), `FGET(f, 1, 2)`, "b,c"},
+		{"FGET_連続したダブルクォートは1つにする", []byte("a,\"say \"\"hi\"\"\"\r\n"), `FGET(f, 1, 2)`, "say \"hi\""},
+		{"FGET_第4引数がFALSEの場合はダブルクォートを残す", []byte("a,\"say \"\"hi\"\"\"\r\n"), `FGET(f, 1, 2, FALSE)`, "\"say \"\"hi\"\"\""},
+		{"FGET_ダブルクォート内の改行は次の行も値に含める", []byte("a,\"b\r\nc\",d\r\n"), `FGET(f, 1, 2)`, "b\nc"},
+		{"FGET_複数行にまたがるレコードの後ろの列を返す", []byte("a,\"b\r\nc\",d\r\n"), `FGET(f, 1, 3)`, "d"},
+		{"FGET_存在しない列はEMPTYを返す", []byte("a,b\r\n"), `EMPTY = FGET(f, 1, 3)`, true},
+		{"FGET_UTF-8のBOMを除いて読み込む", append([]byte{0xEF, 0xBB, 0xBF}, []byte("あ\r\n")...), `FGET(f, 1)`, "あ"},
+		{"FGET_Shift_JISを読み込む", sjis, `FGET(f, 2)`, "えお"},
+		{"FGET_UTF-16を読み込む", utf16, `FGET(f, 1)`, "あいう"},
+		{"FPUT_F_READのみの場合はエラーになる", []byte("a\r\n"), `FPUT(f, "b")`, &object.Error{Message: "file is not opened for writing: "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, tt.content)
+			input := "f = FOPEN(\"" + path + "\", F_READ)\n" + tt.input
+			evaluated := testEval(input)
+			if errObj, ok := tt.expected.(*object.Error); ok {
+				// NOTE: エラーメッセージに含まれるパスは実行ごとに変わる
+				testExpectedObject(t, evaluated, &object.Error{Message: errObj.Message + path})
+				return
+			}
+			testExpectedObject(t, evaluated, tt.expected)
+		})
+	}
+}
+
+func TestFileWrite(t *testing.T) {
+	sjis, _ := japanese.ShiftJIS.NewEncoder().Bytes([]byte("あ\r\n"))
+	tests := []struct {
+		name     string
+		content  []byte
+		input    string
+		expected string
+	}{
+		{
+			"FPUT_F_WRITEの場合は新しい内容で上書きする",
+			[]byte("old\r\n"),
+			`f = FOPEN(path, F_WRITE)
+FPUT(f, "a")
+FPUT(f, "b")
+FCLOSE(f)`,
+			"a\r\nb\r\n",
+		},
+		{
+			"FPUT_行を指定すると書き換える",
+			[]byte("a\r\nb\r\nc\r\n"),
+			`f = FOPEN(path, F_READ OR F_WRITE)
+FPUT(f, "x", 2)
+FCLOSE(f)`,
+			"a\r\nx\r\nc\r\n",
+		},
+		{
+			"FPUT_F_INSERTを指定すると行の前に挿入する",
+			[]byte("a\r\nb\r\n"),
+			`f = FOPEN(path, F_READ OR F_WRITE)
+FPUT(f, "x", 2, F_INSERT)
+FCLOSE(f)`,
+			"a\r\nx\r\nb\r\n",
+		},
+		{
+			"FPUT_列の値にカンマが含まれる場合はダブルクォートで囲む",
+			[]byte("a,b,c\r\n"),
+			`f = FOPEN(path, F_READ OR F_WRITE)
+FPUT(f, "x,y", 1, 2)
+FCLOSE(f)`,
+			"a,\"x,y\",c\r\n",
+		},
+		{
+			"FPUT_既存の列のダブルクォートを保つ",
+			[]byte("\"a,b\",c\r\n"),
+			`f = FOPEN(path, F_READ OR F_WRITE)
+FPUT(f, "x", 1, 2)
+FCLOSE(f)`,
+			"\"a,b\",x\r\n",
+		},
+		{
+			"FPUT_行が足りない場合は空行を追加する",
+			[]byte("a\r\n"),
+			`f = FOPEN(path, F_READ OR F_WRITE)
+FPUT(f, "x", 3)
+FCLOSE(f)`,
+			"a\r\n\r\nx\r\n",
+		},
+		{
+			"FPUT_列を指定するとカンマで区切った値を書き換える",
+			[]byte("a,b\r\n"),
+			`f = FOPEN(path, F_READ OR F_WRITE)
+FPUT(f, "x", 1, 2)
+FPUT(f, "y", 1, 4)
+FCLOSE(f)`,
+			"a,x,,y\r\n",
+		},
+		{
+			"FPUT_F_TABの場合はタブで区切る",
+			[]byte("a\tb\r\n"),
+			`f = FOPEN(path, F_READ OR F_WRITE OR F_TAB)
+FPUT(f, "x", 1, 2)
+FCLOSE(f)`,
+			"a\tx\r\n",
+		},
+		{
+			"FPUT_F_ALLTEXTで全文を置き換える",
+			[]byte("a\r\n"),
+			`f = FOPEN(path, F_READ OR F_WRITE)
+FPUT(f, "x" + FGET(f, 1), F_ALLTEXT)
+FCLOSE(f)`,
+			"xa\r\n",
+		},
+		{
+			"FPUT_F_APPENDの場合は既存の内容の後ろに追加する",
+			[]byte("a\r\nb"),
+			`f = FOPEN(path, F_APPEND)
+FPUT(f, "c")
+FCLOSE(f)`,
+			"a\r\nb\r\nc\r\n",
+		},
+		{
+			"FPUT_F_NOCRの場合は最終行に改行を付けない",
+			[]byte(""),
+			`f = FOPEN(path, F_WRITE OR F_NOCR)
+FPUT(f, "a")
+FPUT(f, "b")
+FCLOSE(f)`,
+			"a\r\nb",
+		},
+		{
+			"FPUT_LFのファイルはLFのまま書き込む",
+			[]byte("a\nb\n"),
+			`f = FOPEN(path, F_READ OR F_WRITE)
+FPUT(f, "c")
+FCLOSE(f)`,
+			"a\nb\nc\n",
+		},
+		{
+			"FPUT_Shift_JISのファイルはShift_JISのまま書き込む",
+			sjis,
+			`f = FOPEN(path, F_READ OR F_WRITE)
+FPUT(f, "い")
+FCLOSE(f)`,
+			"あ\r\nい\r\n",
+		},
+		{
+			"FDELLINE_指定した行を削除する",
+			[]byte("a\r\nb\r\nc\r\n"),
+			`f = FOPEN(path, F_READ OR F_WRITE)
+FDELLINE(f, 2)
+FCLOSE(f)`,
+			"a\r\nc\r\n",
+		},
+		{
+			"FCLOSE_第2引数がTRUEの場合は書き込まない",
+			[]byte("a\r\n"),
+			`f = FOPEN(path, F_READ OR F_WRITE)
+FPUT(f, "b")
+FCLOSE(f, TRUE)`,
+			"a\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, tt.content)
+			evaluated := testEval("path = \"" + path + "\"\n" + tt.input)
+			if errObj, ok := evaluated.(*object.Error); ok {
+				t.Fatalf("unexpected error: %s", errObj.Message)
+			}
+			actual := readTestFile(t, path)
+			if strings.HasPrefix(tt.name, "FPUT_Shift_JIS") {
+				decoded, _ := japanese.ShiftJIS.NewDecoder().Bytes([]byte(actual))
+				actual = string(decoded)
+			}
+			if actual != tt.expected {
+				t.Errorf("file has wrong content. expected=%q, got=%q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFileOpen(t *testing.T) {
+	path := writeTestFile(t, []byte("a\r\n"))
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"FOPEN_F_EXISTSで存在するファイルはTRUEを返す", `FOPEN("` + path + `", F_EXISTS)`, true},
+		{"FOPEN_F_EXISTSで存在しないファイルはFALSEを返す", `FOPEN("` + path + `.none", F_EXISTS)`, false},
+		{"FOPEN_F_EXISTSでディレクトリはFALSEを返す", `FOPEN("` + filepath.Dir(path) + `", F_EXISTS)`, false},
+		{"FOPEN_ファイルオブジェクトを返す", `TYPEOF(FOPEN("` + path + `", F_READ))`, "File"},
+		{
+			"FCLOSE_閉じたファイルを使うとエラーになる",
+			`f = FOPEN("` + path + `", F_READ)
+FCLOSE(f)
+FGET(f, 1)`,
+			&object.Error{Message: "file is already closed: " + path},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testExpectedObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+
+	t.Run("FOPEN_F_READで存在しないファイルはエラーになる", func(t *testing.T) {
+		evaluated := testEval(`FOPEN("` + path + `.none", F_READ)`)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok || !strings.HasPrefix(errObj.Message, "cannot open file:") {
+			t.Errorf("expected open error. got=%T (%+v)", evaluated, evaluated)
+		}
+	})
+}
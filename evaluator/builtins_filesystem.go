@@ -0,0 +1,294 @@
+package evaluator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+var filesystemBuiltinFunctions = map[string]*object.BuiltinFunction{
+	// GETDIR(ディレクトリ, [フィルタ], [取得する種類], [var 配列])
+	// 一致した数を返し、名前の昇順に並べた配列を第4引数の変数に書き戻す
+	// NOTE: UWSCと同様にフィルタの先頭が\の場合はディレクトリを取得する
+	"GETDIR": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 4 {
+				return wrongNumberOfArguments("GETDIR", len(args), "1 to 4")
+			}
+			dir, err := stringArgument("GETDIR", args, 0)
+			if err != nil {
+				return err
+			}
+			filter := "*"
+			if !isOmitted(args, 1) {
+				if filter, err = stringArgument("GETDIR", args, 1); err != nil {
+					return err
+				}
+			}
+			kind, err := integerArgument("GETDIR", args, 2, builtinConstantValue(GD_FILE))
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(filter, `\`) {
+				filter = strings.TrimPrefix(filter, `\`)
+				kind = kind&^builtinConstantValue(GD_FILE) | builtinConstantValue(GD_DIR)
+			}
+			if filter == "" {
+				filter = "*"
+			}
+
+			entries, readErr := os.ReadDir(dir)
+			if readErr != nil {
+				return newError("cannot read directory: %s", readErr)
+			}
+			names := []string{}
+			for _, entry := range entries {
+				if entry.IsDir() && kind&builtinConstantValue(GD_DIR) == 0 ||
+					!entry.IsDir() && kind&builtinConstantValue(GD_FILE) == 0 {
+					continue
+				}
+				if isHiddenName(entry.Name()) && kind&builtinConstantValue(GD_HIDDEN) == 0 {
+					continue
+				}
+				if matchWildcard(filter, entry.Name()) {
+					names = append(names, entry.Name())
+				}
+			}
+			sort.Strings(names)
+
+			count := &object.Integer{Value: int64(len(names))}
+			if isOmitted(args, 3) {
+				return returnResult(count)
+			}
+			elements := []object.Object{}
+			for _, name := range names {
+				elements = append(elements, &object.String{Value: name})
+			}
+			return &object.BuiltinFuncReturnReference{
+				Expression: args[3].Expression,
+				Value:      &object.Array{Elements: elements},
+				Result:     count,
+			}
+		},
+	},
+	// DELETEFILE(ファイル名) ワイルドカードを使える
+	// NOTE: ワイルドカードはGETDIRと同じく非表示ファイルに一致しない
+	"DELETEFILE": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 1 {
+				return wrongNumberOfArguments("DELETEFILE", len(args), "1")
+			}
+			path, err := stringArgument("DELETEFILE", args, 0)
+			if err != nil {
+				return err
+			}
+			files := expandWildcard(path, false)
+			if len(files) == 0 {
+				return returnResult(FALSE)
+			}
+			for _, file := range files {
+				if os.Remove(file) != nil {
+					return returnResult(FALSE)
+				}
+			}
+			return returnResult(TRUE)
+		},
+	},
+	// COPYFILE(コピー元, コピー先) コピー元にはワイルドカードを使える
+	// NOTE: ディレクトリはコピーできない
+	"COPYFILE": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			return transferFiles("COPYFILE", args, false, copyFile)
+		},
+	},
+	// MOVEFILE(移動元, 移動先) 移動元にはワイルドカードを使える
+	// NOTE: ワイルドカードを使わない場合はディレクトリも移動できる
+	"MOVEFILE": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			return transferFiles("MOVEFILE", args, true, os.Rename)
+		},
+	},
+	// CREATEDIR(ディレクトリ) 途中のディレクトリも作成する
+	"CREATEDIR": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 1 {
+				return wrongNumberOfArguments("CREATEDIR", len(args), "1")
+			}
+			dir, err := stringArgument("CREATEDIR", args, 0)
+			if err != nil {
+				return err
+			}
+			return returnResult(nativeBoolToBooleanObject(os.MkdirAll(dir, 0755) == nil))
+		},
+	},
+	// DELETEDIR(ディレクトリ, [中身ごと削除]) 省略時は空のディレクトリのみ削除する
+	"DELETEDIR": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return wrongNumberOfArguments("DELETEDIR", len(args), "1 or 2")
+			}
+			dir, err := stringArgument("DELETEDIR", args, 0)
+			if err != nil {
+				return err
+			}
+			recursive, err := boolArgument("DELETEDIR", args, 1, false)
+			if err != nil {
+				return err
+			}
+			if !isDir(dir) {
+				return returnResult(FALSE)
+			}
+			if recursive {
+				return returnResult(nativeBoolToBooleanObject(os.RemoveAll(dir) == nil))
+			}
+			return returnResult(nativeBoolToBooleanObject(os.Remove(dir) == nil))
+		},
+	},
+	// FILEEXISTS(パス) ファイルが存在するか
+	// NOTE: UWSCにはない独自の関数。UWSCと同じ書き方ではFOPEN(パス, F_EXISTS)を使う
+	"FILEEXISTS": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 1 {
+				return wrongNumberOfArguments("FILEEXISTS", len(args), "1")
+			}
+			path, err := stringArgument("FILEEXISTS", args, 0)
+			if err != nil {
+				return err
+			}
+			info, statErr := os.Stat(path)
+			return returnResult(nativeBoolToBooleanObject(statErr == nil && !info.IsDir()))
+		},
+	},
+	// DIREXISTS(パス) ディレクトリが存在するか
+	// NOTE: UWSCにはない独自の関数。UWSCと同じ書き方ではGETDIRでディレクトリを取得して確かめる
+	"DIREXISTS": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 1 {
+				return wrongNumberOfArguments("DIREXISTS", len(args), "1")
+			}
+			path, err := stringArgument("DIREXISTS", args, 0)
+			if err != nil {
+				return err
+			}
+			return returnResult(nativeBoolToBooleanObject(isDir(path)))
+		},
+	},
+}
+
+const (
+	GD_FILE   = object.BuiltinConstantType("GD_FILE")
+	GD_DIR    = object.BuiltinConstantType("GD_DIR")
+	GD_HIDDEN = object.BuiltinConstantType("GD_HIDDEN")
+)
+
+var filesystemBuiltinConstants = map[object.BuiltinConstantType]object.Object{
+	GD_FILE:   newBuiltinConstant(GD_FILE, 1),
+	GD_DIR:    newBuiltinConstant(GD_DIR, 2),
+	GD_HIDDEN: newBuiltinConstant(GD_HIDDEN, 4),
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// *と?のワイルドカードで大文字小文字を区別せずに比較する
+func matchWildcard(pattern, name string) bool {
+	// NOTE: [はワイルドカードとして扱わない
+	pattern = strings.ReplaceAll(strings.ToLower(pattern), "[", `\[`)
+	matched, err := filepath.Match(pattern, strings.ToLower(name))
+	return err == nil && matched
+}
+
+// NOTE: Linuxでは.から始まるファイルを非表示ファイルとして扱う
+func isHiddenName(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+// ファイル名のワイルドカードを展開する。ワイルドカードがない場合はファイルが存在すればそのまま返す
+// NOTE: ワイルドカードはディレクトリと非表示ファイルに一致しない。withDirがtrueの場合はワイルドカードのないディレクトリも返す
+func expandWildcard(path string, withDir bool) []string {
+	dir, pattern := filepath.Split(path)
+	if !strings.ContainsAny(pattern, "*?") {
+		if info, err := os.Stat(path); err == nil && (withDir || !info.IsDir()) {
+			return []string{path}
+		}
+		return []string{}
+	}
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return []string{}
+	}
+	files := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && !isHiddenName(entry.Name()) && matchWildcard(pattern, entry.Name()) {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return files
+}
+
+// 転送先が既存のディレクトリの場合はその中に同じ名前で転送する
+func transferFiles(name string, args []object.BuiltinFuncArgument, withDir bool, transfer func(src, dst string) error) object.Object {
+	if len(args) != 2 {
+		return wrongNumberOfArguments(name, len(args), "2")
+	}
+	src, err := stringArgument(name, args, 0)
+	if err != nil {
+		return err
+	}
+	dst, err := stringArgument(name, args, 1)
+	if err != nil {
+		return err
+	}
+	files := expandWildcard(src, withDir)
+	if len(files) == 0 {
+		return returnResult(FALSE)
+	}
+	if len(files) > 1 && !isDir(dst) {
+		return returnResult(FALSE)
+	}
+	for _, file := range files {
+		target := dst
+		if isDir(dst) {
+			target = filepath.Join(dst, filepath.Base(file))
+		}
+		if transfer(file, target) != nil {
+			return returnResult(FALSE)
+		}
+	}
+	return returnResult(TRUE)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	// NOTE: 同じファイルに上書きすると中身が消えるためエラーにする
+	if dstInfo, err := os.Stat(dst); err == nil && os.SameFile(info, dstInfo) {
+		return fmt.Errorf("%s and %s are the same file", src, dst)
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
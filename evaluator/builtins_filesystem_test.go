@@ -0,0 +1,220 @@
+package evaluator_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// テスト用のディレクトリに次の構成を作成する
+//
+//	a.txt, b.TXT, c.log, .hidden.txt, sub/d.txt, other/
+func setupTestDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.TXT", "c.log", ".hidden.txt", filepath.Join("sub", "d.txt")} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "other"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestFilesystemBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+		check    func(t *testing.T, dir string)
+	}{
+		{"GETDIR_ファイルの数を返す", `GETDIR(dir)`, 3, nil},
+		{
+			"GETDIR_第4引数の変数に名前の昇順でファイル名を書き戻す",
+			`DIM files[]
+GETDIR(dir, "*", GD_FILE, files)
+JOIN(files, ",")`,
+			"a.txt,b.TXT,c.log",
+			nil,
+		},
+		{
+			"GETDIR_ワイルドカードで大文字小文字を区別せずに絞り込む",
+			`DIM files[]
+GETDIR(dir, "*.txt", GD_FILE, files)
+JOIN(files, ",")`,
+			"a.txt,b.TXT",
+			nil,
+		},
+		{
+			"GETDIR_?は任意の1文字に一致する",
+			`DIM files[]
+GETDIR(dir, "?.log", GD_FILE, files)
+JOIN(files, ",")`,
+			"c.log",
+			nil,
+		},
+		{
+			"GETDIR_GD_DIRでディレクトリを取得する",
+			`DIM files[]
+GETDIR(dir, "*", GD_DIR, files)
+JOIN(files, ",")`,
+			"other,sub",
+			nil,
+		},
+		{
+			"GETDIR_フィルタの先頭が円記号の場合はディレクトリを取得する",
+			`DIM files[]
+GETDIR(dir, "\s*", EMPTY, files)
+JOIN(files, ",")`,
+			"sub",
+			nil,
+		},
+		{
+			"GETDIR_GD_HIDDENで非表示ファイルも取得する",
+			`DIM files[]
+GETDIR(dir, "*.txt", GD_FILE OR GD_HIDDEN, files)
+JOIN(files, ",")`,
+			".hidden.txt,a.txt,b.TXT",
+			nil,
+		},
+		{
+			"DELETEFILE_ファイルを削除する",
+			`DELETEFILE(dir + "/a.txt")`,
+			true,
+			func(t *testing.T, dir string) { assertNotExist(t, filepath.Join(dir, "a.txt")) },
+		},
+		{
+			"DELETEFILE_ワイルドカードで一致したファイルを削除する",
+			`DELETEFILE(dir + "/*.txt")`,
+			true,
+			func(t *testing.T, dir string) {
+				assertNotExist(t, filepath.Join(dir, "a.txt"))
+				assertNotExist(t, filepath.Join(dir, "b.TXT"))
+				assertExist(t, filepath.Join(dir, "c.log"))
+			},
+		},
+		{
+			"DELETEFILE_ワイルドカードは非表示ファイルに一致しない",
+			`DELETEFILE(dir + "/*")`,
+			true,
+			func(t *testing.T, dir string) {
+				assertNotExist(t, filepath.Join(dir, "a.txt"))
+				assertExist(t, filepath.Join(dir, ".hidden.txt"))
+			},
+		},
+		{
+			"DELETEFILE_ワイルドカードを使わない場合は非表示ファイルも削除する",
+			`DELETEFILE(dir + "/.hidden.txt")`,
+			true,
+			func(t *testing.T, dir string) { assertNotExist(t, filepath.Join(dir, ".hidden.txt")) },
+		},
+		{"DELETEFILE_存在しない場合はFALSEを返す", `DELETEFILE(dir + "/none.txt")`, false, nil},
+		{
+			"COPYFILE_ファイルをコピーする",
+			`COPYFILE(dir + "/a.txt", dir + "/copy.txt")`,
+			true,
+			func(t *testing.T, dir string) {
+				assertExist(t, filepath.Join(dir, "a.txt"))
+				assertFileContent(t, filepath.Join(dir, "copy.txt"), "a.txt")
+			},
+		},
+		{
+			"COPYFILE_コピー先がディレクトリの場合はその中にコピーする",
+			`COPYFILE(dir + "/*.txt", dir + "/other")`,
+			true,
+			func(t *testing.T, dir string) {
+				assertFileContent(t, filepath.Join(dir, "other", "a.txt"), "a.txt")
+				assertFileContent(t, filepath.Join(dir, "other", "b.TXT"), "b.TXT")
+			},
+		},
+		{
+			"COPYFILE_同じファイルへのコピーはFALSEを返して中身を残す",
+			`COPYFILE(dir + "/*.txt", dir)`,
+			false,
+			func(t *testing.T, dir string) {
+				assertFileContent(t, filepath.Join(dir, "a.txt"), "a.txt")
+				assertFileContent(t, filepath.Join(dir, "b.TXT"), "b.TXT")
+			},
+		},
+		{"COPYFILE_コピー元が存在しない場合はFALSEを返す", `COPYFILE(dir + "/none.txt", dir + "/copy.txt")`, false, nil},
+		{
+			"MOVEFILE_ファイルを移動する",
+			`MOVEFILE(dir + "/a.txt", dir + "/sub")`,
+			true,
+			func(t *testing.T, dir string) {
+				assertNotExist(t, filepath.Join(dir, "a.txt"))
+				assertFileContent(t, filepath.Join(dir, "sub", "a.txt"), "a.txt")
+			},
+		},
+		{
+			"MOVEFILE_ディレクトリを移動する",
+			`MOVEFILE(dir + "/sub", dir + "/other")`,
+			true,
+			func(t *testing.T, dir string) {
+				assertNotExist(t, filepath.Join(dir, "sub"))
+				assertFileContent(t, filepath.Join(dir, "other", "sub", "d.txt"), "sub/d.txt")
+			},
+		},
+		{"COPYFILE_ディレクトリはコピーできずFALSEを返す", `COPYFILE(dir + "/sub", dir + "/other")`, false, nil},
+		{
+			"CREATEDIR_途中のディレクトリも作成する",
+			`CREATEDIR(dir + "/x/y")`,
+			true,
+			func(t *testing.T, dir string) { assertExist(t, filepath.Join(dir, "x", "y")) },
+		},
+		{"DELETEDIR_空でないディレクトリは削除しない", `DELETEDIR(dir + "/sub")`, false, nil},
+		{
+			"DELETEDIR_空のディレクトリを削除する",
+			`DELETEDIR(dir + "/other")`,
+			true,
+			func(t *testing.T, dir string) { assertNotExist(t, filepath.Join(dir, "other")) },
+		},
+		{
+			"DELETEDIR_第2引数がTRUEの場合は中身ごと削除する",
+			`DELETEDIR(dir + "/sub", TRUE)`,
+			true,
+			func(t *testing.T, dir string) { assertNotExist(t, filepath.Join(dir, "sub")) },
+		},
+		{"FILEEXISTS_ファイルが存在する場合はTRUEを返す", `FILEEXISTS(dir + "/a.txt")`, true, nil},
+		{"FILEEXISTS_ディレクトリの場合はFALSEを返す", `FILEEXISTS(dir + "/sub")`, false, nil},
+		{"DIREXISTS_ディレクトリが存在する場合はTRUEを返す", `DIREXISTS(dir + "/sub")`, true, nil},
+		{"DIREXISTS_存在しない場合はFALSEを返す", `DIREXISTS(dir + "/none")`, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setupTestDir(t)
+			testExpectedObject(t, testEval("dir = \""+dir+"\"\n"+tt.input), tt.expected)
+			if tt.check != nil {
+				tt.check(t, dir)
+			}
+		})
+	}
+}
+
+func assertExist(t *testing.T, path string) {
+	t.Helper()
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("%s should exist: %s", path, err)
+	}
+}
+
+func assertNotExist(t *testing.T, path string) {
+	t.Helper()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("%s should not exist", path)
+	}
+}
+
+func assertFileContent(t *testing.T, path, expected string) {
+	t.Helper()
+	if actual := readTestFile(t, path); actual != expected {
+		t.Errorf("file has wrong content. expected=%q, got=%q", expected, actual)
+	}
+}
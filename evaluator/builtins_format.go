@@ -0,0 +1,128 @@
+package evaluator
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+var formatBuiltinFunctions = map[string]*object.BuiltinFunction{
+	// FORMAT(数値, 幅, [小数点以下の桁数], [埋め文字])
+	// FORMAT(文字列, 幅) 幅の分だけ文字列を繰り返す
+	// FORMAT(日時, 書式) 日時の文字列またはGETTIMEの値を書式に従って文字列にする
+	"FORMAT": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 2 || len(args) > 4 {
+				return wrongNumberOfArguments("FORMAT", len(args), "2 to 4")
+			}
+			if layout, ok := args[1].Value.(*object.String); ok {
+				return formatDate(args, layout.Value)
+			}
+			width, err := integerArgument("FORMAT", args, 1, 0)
+			if err != nil {
+				return err
+			}
+			if str, ok := args[0].Value.(*object.String); ok {
+				return returnResult(&object.String{Value: repeatToWidth(str.Value, int(width))})
+			}
+			digits, err := integerArgument("FORMAT", args, 2, 0)
+			if err != nil {
+				return err
+			}
+			fill := " "
+			if !isOmitted(args, 3) {
+				if fill, err = stringArgument("FORMAT", args, 3); err != nil {
+					return err
+				}
+			}
+
+			var str string
+			switch arg := args[0].Value.(type) {
+			case *object.Integer:
+				str, err = formatInteger(arg.Value, digits)
+			case *object.Float:
+				str, err = formatFloat(arg.Value, digits)
+			default:
+				return newError("argument 1 to `FORMAT` not supported, got %s", args[0].Value.Type())
+			}
+			if err != nil {
+				return err
+			}
+			return returnResult(&object.String{Value: pad(str, int(width), fill)})
+		},
+	},
+}
+
+// NOTE: 2^53を超える値の精度が落ちないように、整数はfloat64を経由せずに表示する
+func formatInteger(value, digits int64) (string, *object.Error) {
+	switch {
+	case digits == -1:
+		return strings.ToUpper(strconv.FormatUint(uint64(value), 16)), nil
+	case digits == -2:
+		return strconv.FormatUint(uint64(value), 16), nil
+	case digits == -3:
+		return strconv.FormatUint(uint64(value), 2), nil
+	case digits < 0:
+		return "", newError("argument 3 to `FORMAT` not supported, got %d", digits)
+	case digits == 0:
+		return strconv.FormatInt(value, 10), nil
+	default:
+		return strconv.FormatInt(value, 10) + "." + strings.Repeat("0", int(digits)), nil
+	}
+}
+
+func formatFloat(value float64, digits int64) (string, *object.Error) {
+	if digits < 0 {
+		return formatInteger(int64(value), digits)
+	}
+	// NOTE: 四捨五入してから指定の桁数で表示する
+	scale := math.Pow(10, float64(digits))
+	rounded := math.Round(value*scale) / scale
+	if rounded == 0 {
+		// NOTE: -0と表示されないようにする
+		rounded = 0
+	}
+	return strconv.FormatFloat(rounded, 'f', int(digits), 64), nil
+}
+
+// 幅に満たない場合は埋め文字で埋める。幅がマイナスの場合は左寄せにする
+func pad(str string, width int, fill string) string {
+	leftAlign := width < 0
+	if leftAlign {
+		width = -width
+	}
+	length := utf8.RuneCountInString(str)
+	if fill == "" || length >= width {
+		return str
+	}
+	padding := repeatToWidth(fill, width-length)
+	if leftAlign {
+		return str + padding
+	}
+	// NOTE: 0埋めの場合は符号の後ろを埋める
+	if fill == "0" && strings.HasPrefix(str, "-") {
+		return "-" + padding + str[1:]
+	}
+	return padding + str
+}
+
+// 文字列を繰り返して指定した文字数にする
+func repeatToWidth(str string, width int) string {
+	runes := []rune(str)
+	if len(runes) == 0 || width <= 0 {
+		return ""
+	}
+	repeated := []rune(strings.Repeat(str, width/len(runes)+1))
+	return string(repeated[:width])
+}
+
+func formatDate(args []object.BuiltinFuncArgument, layout string) object.Object {
+	t, err := dateArgument("FORMAT", args, 0)
+	if err != nil {
+		return err
+	}
+	return returnResult(&object.String{Value: formatDateTime(t, layout)})
+}
@@ -0,0 +1,44 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+func TestFormatBuiltinFunction(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"FORMAT_幅に満たない場合は空白で埋めて右寄せにする", `FORMAT(12, 5)`, "   12"},
+		{"FORMAT_幅がマイナスの場合は左寄せにする", `FORMAT(12, -5)`, "12   "},
+		{"FORMAT_幅を超える場合はそのまま返す", `FORMAT(123456, 3)`, "123456"},
+		{"FORMAT_埋め文字で0埋めする", `FORMAT(7, 3, 0, "0")`, "007"},
+		{"FORMAT_マイナスの数値は符号の後ろを0埋めする", `FORMAT(-7, 4, 0, "0")`, "-007"},
+		{"FORMAT_小数点以下の桁数を指定する", `FORMAT(5, 0, 2)`, "5.00"},
+		{"FORMAT_小数点以下を四捨五入する", `FORMAT(VAL("0.6665"), 0, 3)`, "0.667"},
+		{"FORMAT_桁数が0の場合は整数に四捨五入する", `FORMAT(VAL("2.5"), 0)`, "3"},
+		{"FORMAT_0に丸めた場合はマイナスを付けない", `FORMAT(VAL("-0.1"), 0)`, "0"},
+		{"FORMAT_2の53乗を超える整数も精度を落とさない", `FORMAT(9007199254740993, 0)`, "9007199254740993"},
+		{"FORMAT_2の53乗を超える整数に小数点以下の桁数を指定する", `FORMAT(9007199254740993, 0, 2)`, "9007199254740993.00"},
+		{"FORMAT_2の53乗を超える整数を16進数にする", `FORMAT(9007199254740993, 0, -1)`, "20000000000001"},
+		{"FORMAT_小数の場合は整数部分を16進数にする", `FORMAT(VAL("255.9"), 0, -1)`, "FF"},
+		{"FORMAT_桁数が-1の場合は大文字の16進数にする", `FORMAT(255, 4, -1, "0")`, "00FF"},
+		{"FORMAT_桁数が-2の場合は小文字の16進数にする", `FORMAT(255, 0, -2)`, "ff"},
+		{"FORMAT_桁数が-3の場合は2進数にする", `FORMAT(5, 8, -3, "0")`, "00000101"},
+		{"FORMAT_文字列の場合は幅の分だけ繰り返す", `FORMAT("=-", 5)`, "=-=-="},
+		{"FORMAT_日付文字列を書式に従って変換する", `FORMAT("2021/03/04 05:06:07", "yyyy年mm月dd日 hh:nn:ss")`, "2021年03月04日 05:06:07"},
+		{"FORMAT_曜日を変換する", `FORMAT("2021/03/04", "yy/mm/dd(aaa) dddd")`, "21/03/04(木) Thursday"},
+		{"FORMAT_GETTIMEの値を変換する", `FORMAT(GETTIME(0, "2021/03/04 05:06:07"), "yyyy/mm/dd hh:nn:ss")`, "2021/03/04 05:06:07"},
+		{"FORMAT_日付として解釈できない場合はエラーになる", `FORMAT("abc", "yyyy")`, &object.Error{Message: "cannot parse \"abc\" as date in `FORMAT`"}},
+		{"FORMAT_桁数が-4以下の場合はエラーになる", `FORMAT(1, 0, -4)`, &object.Error{Message: "argument 3 to `FORMAT` not supported, got -4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testExpectedObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
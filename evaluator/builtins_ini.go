@@ -0,0 +1,128 @@
+package evaluator
+
+import (
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+var iniBuiltinFunctions = map[string]*object.BuiltinFunction{
+	// READINI([セクション], [キー], ファイル名)
+	// セクションを省略した場合はセクションの一覧、キーを省略した場合はキーの一覧を配列で返す
+	"READINI": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 3 {
+				return wrongNumberOfArguments("READINI", len(args), "3")
+			}
+			section, key, err := iniSectionAndKey("READINI", args)
+			if err != nil {
+				return err
+			}
+			ini, _, err := iniArgument("READINI", args, 2)
+			if err != nil {
+				return err
+			}
+			if section == "" {
+				return returnResult(stringsToArray(ini.sections()))
+			}
+			if key == "" {
+				return returnResult(stringsToArray(ini.keys(section)))
+			}
+			value, _ := ini.get(section, key)
+			return returnResult(&object.String{Value: value})
+		},
+	},
+	// WRITEINI(セクション, キー, 値, ファイル名)
+	"WRITEINI": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 4 {
+				return wrongNumberOfArguments("WRITEINI", len(args), "4")
+			}
+			section, key, err := iniSectionAndKey("WRITEINI", args)
+			if err != nil {
+				return err
+			}
+			if section == "" || key == "" {
+				return newError("section and key to `WRITEINI` should not be empty")
+			}
+			ini, path, err := iniArgument("WRITEINI", args, 3)
+			if err != nil {
+				return err
+			}
+			ini.set(section, key, toStringValue(args[2].Value))
+			return writeIni(ini, path)
+		},
+	},
+	// DELETEINI(セクション, [キー], ファイル名) キーを省略した場合はセクションごと削除する
+	"DELETEINI": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 3 {
+				return wrongNumberOfArguments("DELETEINI", len(args), "3")
+			}
+			section, key, err := iniSectionAndKey("DELETEINI", args)
+			if err != nil {
+				return err
+			}
+			if section == "" {
+				return newError("section to `DELETEINI` should not be empty")
+			}
+			ini, path, err := iniArgument("DELETEINI", args, 2)
+			if err != nil {
+				return err
+			}
+			deleted := false
+			if key == "" {
+				deleted = ini.deleteSection(section)
+			} else {
+				deleted = ini.deleteKey(section, key)
+			}
+			if !deleted {
+				return returnResult(NULL)
+			}
+			return writeIni(ini, path)
+		},
+	},
+}
+
+// 省略された場合は空文字を返す
+func iniSectionAndKey(name string, args []object.BuiltinFuncArgument) (string, string, *object.Error) {
+	section, key := "", ""
+	var err *object.Error
+	if !isOmitted(args, 0) {
+		if section, err = stringArgument(name, args, 0); err != nil {
+			return "", "", err
+		}
+	}
+	if !isOmitted(args, 1) {
+		if key, err = stringArgument(name, args, 1); err != nil {
+			return "", "", err
+		}
+	}
+	return section, key, nil
+}
+
+// ファイルが存在しない場合は空のINIファイルとして扱う
+func iniArgument(name string, args []object.BuiltinFuncArgument, i int) (*iniFile, string, *object.Error) {
+	path, err := stringArgument(name, args, i)
+	if err != nil {
+		return nil, "", err
+	}
+	ini, readErr := readIniFile(path)
+	if readErr != nil {
+		return nil, "", newError("cannot read ini file: %s", readErr)
+	}
+	return ini, path, nil
+}
+
+func writeIni(ini *iniFile, path string) object.Object {
+	if err := ini.write(path); err != nil {
+		return newError("cannot write ini file: %s", err)
+	}
+	return returnResult(NULL)
+}
+
+func stringsToArray(values []string) *object.Array {
+	elements := []object.Object{}
+	for _, v := range values {
+		elements = append(elements, &object.String{Value: v})
+	}
+	return &object.Array{Elements: elements}
+}
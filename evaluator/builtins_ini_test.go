@@ -0,0 +1,156 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/sam8helloworld/uwscgo/object"
+	"golang.org/x/text/encoding/japanese"
+)
+
+const testIni = "; 設定ファイル\r\n" +
+	"[General]\r\n" +
+	"Name=uwscgo\r\n" +
+	"; 引用符は除く\r\n" +
+	"Title = \"Hello World\"\r\n" +
+	"\r\n" +
+	"[Log]\r\n" +
+	"Level=debug ; 行末のコメント\r\n"
+
+func TestReadIni(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"READINI_キーの値を返す", `READINI("General", "Name", path)`, "uwscgo"},
+		{"READINI_セクションとキーは大文字小文字を区別しない", `READINI("general", "NAME", path)`, "uwscgo"},
+		{"READINI_値を囲むダブルクォートと前後の空白を除く", `READINI("General", "Title", path)`, "Hello World"},
+		{"READINI_行末のコメントを除く", `READINI("Log", "Level", path)`, "debug"},
+		{"READINI_存在しないキーは空文字を返す", `READINI("General", "None", path)`, ""},
+		{"READINI_存在しないファイルは空文字を返す", `READINI("General", "Name", path + ".none")`, ""},
+		{"READINI_キーを省略した場合はキーの一覧を返す", `JOIN(READINI("General", , path), ",")`, "Name,Title"},
+		{"READINI_セクションを省略した場合はセクションの一覧を返す", `JOIN(READINI(, , path), ",")`, "General,Log"},
+		{"READINI_セクションに空文字を指定した場合はセクションの一覧を返す", `JOIN(READINI("", "", path), ",")`, "General,Log"},
+		{"WRITEINI_セクションが空の場合はエラーになる", `WRITEINI("", "a", 1, path)`, &object.Error{Message: "section and key to `WRITEINI` should not be empty"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, []byte(testIni))
+			testExpectedObject(t, testEval("path = \""+path+"\"\n"+tt.input), tt.expected)
+		})
+	}
+}
+
+func TestWriteIni(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			"WRITEINI_既存のキーは同じ行を書き換える",
+			`WRITEINI("general", "name", "changed", path)`,
+			"; 設定ファイル\r\n[General]\r\nName=changed\r\n; 引用符は除く\r\nTitle = \"Hello World\"\r\n\r\n[Log]\r\nLevel=debug ; 行末のコメント\r\n",
+		},
+		{
+			"WRITEINI_既存の行の空白とダブルクォートを保つ",
+			`WRITEINI("General", "Title", "changed", path)`,
+			"; 設定ファイル\r\n[General]\r\nName=uwscgo\r\n; 引用符は除く\r\nTitle = \"changed\"\r\n\r\n[Log]\r\nLevel=debug ; 行末のコメント\r\n",
+		},
+		{
+			"WRITEINI_行末のコメントを保つ",
+			`WRITEINI("Log", "Level", "info", path)`,
+			"; 設定ファイル\r\n[General]\r\nName=uwscgo\r\n; 引用符は除く\r\nTitle = \"Hello World\"\r\n\r\n[Log]\r\nLevel=info ; 行末のコメント\r\n",
+		},
+		{
+			"WRITEINI_同じ値の場合は行を変更しない",
+			`WRITEINI("General", "Title", "Hello World", path)`,
+			testIni,
+		},
+		{
+			"WRITEINI_新しいキーはセクションの最後に追加する",
+			`WRITEINI("General", "Count", 3, path)`,
+			"; 設定ファイル\r\n[General]\r\nName=uwscgo\r\n; 引用符は除く\r\nTitle = \"Hello World\"\r\nCount=3\r\n\r\n[Log]\r\nLevel=debug ; 行末のコメント\r\n",
+		},
+		{
+			"WRITEINI_新しいセクションはファイルの最後に追加する",
+			`WRITEINI("New", "Key", "v", path)`,
+			testIni + "\r\n[New]\r\nKey=v\r\n",
+		},
+		{
+			"DELETEINI_キーを削除する",
+			`DELETEINI("General", "Title", path)`,
+			"; 設定ファイル\r\n[General]\r\nName=uwscgo\r\n; 引用符は除く\r\n\r\n[Log]\r\nLevel=debug ; 行末のコメント\r\n",
+		},
+		{
+			"DELETEINI_キーを省略した場合はセクションごと削除する",
+			`DELETEINI("Log", , path)`,
+			"; 設定ファイル\r\n[General]\r\nName=uwscgo\r\n; 引用符は除く\r\nTitle = \"Hello World\"\r\n\r\n",
+		},
+		{
+			"DELETEINI_存在しないキーの場合は変更しない",
+			`DELETEINI("General", "None", path)`,
+			testIni,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, []byte(testIni))
+			evaluated := testEval("path = \"" + path + "\"\n" + tt.input)
+			if errObj, ok := evaluated.(*object.Error); ok {
+				t.Fatalf("unexpected error: %s", errObj.Message)
+			}
+			if actual := readTestFile(t, path); actual != tt.expected {
+				t.Errorf("file has wrong content. expected=%q, got=%q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestIniShiftJIS(t *testing.T) {
+	sjis, _ := japanese.ShiftJIS.NewEncoder().Bytes([]byte("[設定]\r\n名前=テスト\r\n"))
+	path := writeTestFile(t, sjis)
+
+	testStringObject(t, testEval(`READINI("設定", "名前", "`+path+`")`), "テスト")
+
+	testEval(`WRITEINI("設定", "値", "日本語", "` + path + `")`)
+	decoded, err := japanese.ShiftJIS.NewDecoder().Bytes([]byte(readTestFile(t, path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[設定]\r\n名前=テスト\r\n値=日本語\r\n"
+	if string(decoded) != expected {
+		t.Errorf("file has wrong content. expected=%q, got=%q", expected, string(decoded))
+	}
+}
+
+func TestIniDefaultEncoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{"ASCIIのみのファイルはShift_JISで書き込む", []byte("[General]\r\nName=uwscgo\r\n")},
+		{"新しいファイルもShift_JISで書き込む", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, tt.content)
+			if tt.content == nil {
+				path += ".new"
+			}
+
+			testEval(`WRITEINI("General", "Name", "日本語", "` + path + `")`)
+			decoded, err := japanese.ShiftJIS.NewDecoder().Bytes([]byte(readTestFile(t, path)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := "[General]\r\nName=日本語\r\n"
+			if string(decoded) != expected {
+				t.Errorf("file has wrong content. expected=%q, got=%q", expected, string(decoded))
+			}
+		})
+	}
+}
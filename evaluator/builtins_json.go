@@ -0,0 +1,182 @@
+package evaluator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+var jsonBuiltinFunctions = map[string]*object.BuiltinFunction{
+	// FROMJSON(JSON文字列)
+	// オブジェクトはキーの順番を保ったハッシュテーブル、配列は配列、nullはNULLにする
+	"FROMJSON": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 1 {
+				return wrongNumberOfArguments("FROMJSON", len(args), "1")
+			}
+			text, err := stringArgument("FROMJSON", args, 0)
+			if err != nil {
+				return err
+			}
+			dec := json.NewDecoder(strings.NewReader(text))
+			dec.UseNumber()
+			value, decodeErr := decodeJSON(dec)
+			if decodeErr == nil {
+				// NOTE: 値の後ろに余分な文字が続く場合もエラーにする
+				if _, tokenErr := dec.Token(); tokenErr != io.EOF {
+					decodeErr = fmt.Errorf("unexpected data after top-level value")
+				}
+			}
+			if decodeErr != nil {
+				return newError("failed to parse json in `FROMJSON`: %s", decodeErr)
+			}
+			return returnResult(value)
+		},
+	},
+	// TOJSON(値, [整形する=FALSE])
+	"TOJSON": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return wrongNumberOfArguments("TOJSON", len(args), "1 or 2")
+			}
+			pretty, err := boolArgument("TOJSON", args, 1, false)
+			if err != nil {
+				return err
+			}
+			var out bytes.Buffer
+			if err := encodeJSON(&out, args[0].Value); err != nil {
+				return err
+			}
+			if !pretty {
+				return returnResult(&object.String{Value: out.String()})
+			}
+			var indented bytes.Buffer
+			if err := json.Indent(&indented, out.Bytes(), "", "  "); err != nil {
+				return newError("failed to format json in `TOJSON`: %s", err)
+			}
+			return returnResult(&object.String{Value: indented.String()})
+		},
+	},
+}
+
+func decodeJSON(dec *json.Decoder) (object.Object, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '[' {
+			elements := []object.Object{}
+			for dec.More() {
+				element, err := decodeJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, element)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return &object.Array{Elements: elements}, nil
+		}
+		// NOTE: JSONのキーは大文字小文字を区別する
+		hash := object.NewHashTable(false, true)
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			hash.Set(&object.String{Value: key.(string)}, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return hash, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return &object.Integer{Value: i}, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return &object.Float{Value: f}, nil
+	case string:
+		return &object.String{Value: v}, nil
+	case bool:
+		return nativeBoolToBooleanObject(v), nil
+	default:
+		return NULL, nil
+	}
+}
+
+func encodeJSON(out *bytes.Buffer, value object.Object) *object.Error {
+	switch v := value.(type) {
+	case *object.HashTable:
+		out.WriteString("{")
+		for i, pair := range v.Pairs() {
+			if i > 0 {
+				out.WriteString(",")
+			}
+			writeJSONString(out, pair.Key.Inspect())
+			out.WriteString(":")
+			if err := encodeJSON(out, pair.Value); err != nil {
+				return err
+			}
+		}
+		out.WriteString("}")
+	case *object.Array:
+		out.WriteString("[")
+		for i, element := range v.Elements {
+			if i > 0 {
+				out.WriteString(",")
+			}
+			if err := encodeJSON(out, element); err != nil {
+				return err
+			}
+		}
+		out.WriteString("]")
+	case *object.String:
+		writeJSONString(out, v.Value)
+	case *object.Integer:
+		out.WriteString(strconv.FormatInt(v.Value, 10))
+	case *object.Float:
+		b, err := json.Marshal(v.Value)
+		if err != nil {
+			return newError("cannot convert %s to json in `TOJSON`", v.Inspect())
+		}
+		out.Write(b)
+	case *object.Boolean:
+		if v.Value {
+			out.WriteString("true")
+		} else {
+			out.WriteString("false")
+		}
+	default:
+		// NOTE: EMPTYとNULLはどちらもnullにする
+		if value == NULL || value == EMPTY {
+			out.WriteString("null")
+			return nil
+		}
+		return newError("cannot convert %s to json in `TOJSON`", value.Type())
+	}
+	return nil
+}
+
+func writeJSONString(out *bytes.Buffer, s string) {
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	// NOTE: Encodeは末尾に改行を付ける
+	out.Truncate(out.Len() - 1)
+}
@@ -0,0 +1,63 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+// NOTE: 文字列の中のダブルクォートは""と書く
+const testJSON = `text = "{""name"": ""uwscgo"", ""version"": 1.5, ""tags"": [""go"", ""uwsc""],` +
+	` ""owner"": {""id"": 10, ""active"": true, ""note"": null}, ""Name"": ""upper""}"` + "\n"
+
+func TestJSONBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"FROMJSON_オブジェクトはハッシュテーブルになる", `VARTYPE(FROMJSON(text))`, 512},
+		{"FROMJSON_キーは大文字小文字を区別する", `FROMJSON(text)["Name"]`, "upper"},
+		{"FROMJSON_整数は整数になる", `FROMJSON(text)["owner"]["id"] + 1`, 11},
+		{"FROMJSON_小数は実数になる", `FROMJSON(text)["version"]`, 1.5},
+		{"FROMJSON_真偽値", `FROMJSON(text)["owner"]["active"]`, true},
+		{"FROMJSON_nullはNULLになる", `NULL = FROMJSON(text)["owner"]["note"]`, true},
+		{"FROMJSON_配列は配列になる", `JOIN(FROMJSON(text)["tags"], ",")`, "go,uwsc"},
+		{"FROMJSON_ドットでメンバーを参照する", `json = FROMJSON(text)
+json.owner.id`, 10},
+		{"FROMJSON_ドットと添字を組み合わせる", `json = FROMJSON(text)
+json.tags[1]`, "uwsc"},
+		{"FROMJSON_存在しないメンバーはNULLを返す", `json = FROMJSON(text)
+NULL = json.none`, true},
+		{"FROMJSON_ドットでメンバーに代入する", `json = FROMJSON(text)
+json.owner.id = 5
+json.owner.id`, 5},
+		{"FROMJSON_ドットで新しいメンバーを追加する", `json = FROMJSON(text)
+json.owner.tags = json.tags
+TOJSON(json.owner)`, `{"id":10,"active":true,"note":null,"tags":["go","uwsc"]}`},
+		{"FROMJSON_ドットと添字で代入する", `json = FROMJSON(text)
+json.tags[0] = "golang"
+json.tags[0]`, "golang"},
+		{"FROMJSON_ハッシュテーブル以外のメンバーへの代入はエラーになる", `json = FROMJSON(text)
+json.name.x = 1`, &object.Error{Message: "dot operator not supported: STRING"}},
+		{"FROMJSON_ハッシュテーブル以外のメンバーはエラーになる", `json = FROMJSON(text)
+json.name.length`, &object.Error{Message: "dot operator not supported: STRING"}},
+		{"FROMJSON_文字列の値", `FROMJSON("""a\""b""")`, "a\"b"},
+		{"FROMJSON_値の後ろに余分な文字があるとエラーになる", `FROMJSON("1 2")`, &object.Error{Message: "failed to parse json in `FROMJSON`: unexpected data after top-level value"}},
+		{"TOJSON_キーの順番を保って出力する", `TOJSON(FROMJSON(text))`, `{"name":"uwscgo","version":1.5,"tags":["go","uwsc"],"owner":{"id":10,"active":true,"note":null},"Name":"upper"}`},
+		{"TOJSON_整形して出力する", `TOJSON(FROMJSON(text)["owner"], TRUE)`, "{\n  \"id\": 10,\n  \"active\": true,\n  \"note\": null\n}"},
+		{"TOJSON_HTMLの記号はエスケープしない", `TOJSON("<a&b>")`, `"<a&b>"`},
+		{"TOJSON_ダブルクォートはエスケープする", `TOJSON(text)`, `"{\"name\": \"uwscgo\", \"version\": 1.5, \"tags\": [\"go\", \"uwsc\"], \"owner\": {\"id\": 10, \"active\": true, \"note\": null}, \"Name\": \"upper\"}"`},
+		{"TOJSON_EMPTYはnullになる", `TOJSON(EMPTY)`, "null"},
+		{"TOJSON_HASHTBLを出力する", `HASHTBL hash
+hash["b"] = 1
+hash["a"] = "x"
+TOJSON(hash)`, `{"b":1,"a":"x"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testExpectedObject(t, testEval(testJSON+tt.input), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package evaluator
+
+import (
+	"math"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+var mathBuiltinFunctions = map[string]*object.BuiltinFunction{
+	"ABS": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 1 {
+				return wrongNumberOfArguments("ABS", len(args), "1")
+			}
+			num, err := numberArgument("ABS", args, 0)
+			if err != nil {
+				return err
+			}
+			if i, ok := num.(*object.Integer); ok && i.Value != math.MinInt64 {
+				if i.Value < 0 {
+					return returnResult(&object.Integer{Value: -i.Value})
+				}
+				return returnResult(i)
+			}
+			return returnResult(newNumber(math.Abs(toFloat(num))))
+		},
+	},
+	// INT(数値) 小数点以下を切り捨てる
+	"INT": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			return roundBy("INT", args, math.Trunc)
+		},
+	},
+	// CEIL(数値) 小数点以下を切り上げる
+	"CEIL": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			return roundBy("CEIL", args, math.Ceil)
+		},
+	},
+	// ROUND(数値, [桁]) 桁がマイナスの場合は小数点以下の桁、プラスの場合は整数部の桁で四捨五入する
+	"ROUND": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return wrongNumberOfArguments("ROUND", len(args), "1 or 2")
+			}
+			num, err := numberArgument("ROUND", args, 0)
+			if err != nil {
+				return err
+			}
+			digits, err := integerArgument("ROUND", args, 1, 0)
+			if err != nil {
+				return err
+			}
+			// NOTE: 10のべき乗がfloat64で表せないほど桁が大きい場合、整数部の桁なら0を返し、小数点以下の桁なら丸めずに返す
+			scale := math.Pow(10, float64(-digits))
+			if scale == 0 {
+				return returnResult(&object.Integer{Value: 0})
+			}
+			scaled := toFloat(num) * scale
+			if math.IsInf(scale, 0) || math.IsInf(scaled, 0) {
+				return returnResult(num)
+			}
+			return returnResult(newNumber(math.Round(scaled) / scale))
+		},
+	},
+	"SQRT": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			return mathFunction("SQRT", args, func(x float64) (float64, bool) {
+				return math.Sqrt(x), x >= 0
+			})
+		},
+	},
+	// POWER(底, 指数)
+	"POWER": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 2 {
+				return wrongNumberOfArguments("POWER", len(args), "2")
+			}
+			base, err := numberArgument("POWER", args, 0)
+			if err != nil {
+				return err
+			}
+			exponent, err := numberArgument("POWER", args, 1)
+			if err != nil {
+				return err
+			}
+			result := math.Pow(toFloat(base), toFloat(exponent))
+			if math.IsNaN(result) || math.IsInf(result, 0) {
+				return newError("domain error in `POWER`: %s, %s", base.Inspect(), exponent.Inspect())
+			}
+			return returnResult(newNumber(result))
+		},
+	},
+	"EXP": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			return mathFunction("EXP", args, func(x float64) (float64, bool) {
+				result := math.Exp(x)
+				return result, !math.IsInf(result, 0)
+			})
+		},
+	},
+	// LOGN(数値) 自然対数を返す
+	"LOGN": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			return mathFunction("LOGN", args, func(x float64) (float64, bool) {
+				return math.Log(x), x > 0
+			})
+		},
+	},
+	"SIN": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			return mathFunction("SIN", args, func(x float64) (float64, bool) {
+				return math.Sin(x), true
+			})
+		},
+	},
+	"COS": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			return mathFunction("COS", args, func(x float64) (float64, bool) {
+				return math.Cos(x), true
+			})
+		},
+	},
+	"TAN": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			return mathFunction("TAN", args, func(x float64) (float64, bool) {
+				return math.Tan(x), true
+			})
+		},
+	},
+	"ARCTAN": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			return mathFunction("ARCTAN", args, func(x float64) (float64, bool) {
+				return math.Atan(x), true
+			})
+		},
+	},
+	// ZCUT(数値) マイナスの場合は0を返す
+	"ZCUT": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 1 {
+				return wrongNumberOfArguments("ZCUT", len(args), "1")
+			}
+			num, err := numberArgument("ZCUT", args, 0)
+			if err != nil {
+				return err
+			}
+			if toFloat(num) < 0 {
+				return returnResult(&object.Integer{Value: 0})
+			}
+			return returnResult(num)
+		},
+	},
+	// RANDOM(上限) 0以上上限未満の整数を返す
+	"RANDOM": {
+		EnvFn: func(env *object.Environment, args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 1 {
+				return wrongNumberOfArguments("RANDOM", len(args), "1")
+			}
+			max, err := integerArgument("RANDOM", args, 0, 0)
+			if err != nil {
+				return err
+			}
+			if max <= 0 {
+				return newError("argument 1 to `RANDOM` should be positive, got %d", max)
+			}
+			return returnResult(&object.Integer{Value: env.Random().Int63n(max)})
+		},
+	},
+}
+
+// 数値または数値として解釈できる文字列の引数を返す
+func numberArgument(name string, args []object.BuiltinFuncArgument, i int) (object.Object, *object.Error) {
+	num, ok := toNumber(args[i].Value)
+	if !ok {
+		return nil, newError("argument %d to `%s` not supported, got %s", i+1, name, args[i].Value.Type())
+	}
+	return num, nil
+}
+
+// 整数で表せる場合はInteger、それ以外はFloatを返す
+func newNumber(f float64) object.Object {
+	if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+		return &object.Integer{Value: int64(f)}
+	}
+	return &object.Float{Value: f}
+}
+
+func roundBy(name string, args []object.BuiltinFuncArgument, round func(float64) float64) object.Object {
+	if len(args) != 1 {
+		return wrongNumberOfArguments(name, len(args), "1")
+	}
+	num, err := numberArgument(name, args, 0)
+	if err != nil {
+		return err
+	}
+	if _, ok := num.(*object.Integer); ok {
+		return returnResult(num)
+	}
+	return returnResult(newNumber(round(toFloat(num))))
+}
+
+// 1引数の数学関数を呼び出す。定義域外の場合はエラーを返す
+func mathFunction(name string, args []object.BuiltinFuncArgument, fn func(float64) (float64, bool)) object.Object {
+	if len(args) != 1 {
+		return wrongNumberOfArguments(name, len(args), "1")
+	}
+	num, err := numberArgument(name, args, 0)
+	if err != nil {
+		return err
+	}
+	result, ok := fn(toFloat(num))
+	if !ok || math.IsNaN(result) {
+		return newError("domain error in `%s`: %s", name, num.Inspect())
+	}
+	return returnResult(newNumber(result))
+}
@@ -0,0 +1,76 @@
+package evaluator_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+func TestMathBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"ABS_マイナスの整数の絶対値を返す", `ABS(-5)`, 5},
+		{"ABS_実数の絶対値を返す", `ABS(VAL("-1.5"))`, 1.5},
+		{"ABS_数値に変換できる文字列を扱える", `ABS("-3")`, 3},
+		{"INT_小数点以下を切り捨てる", `INT(VAL("2.7"))`, 2},
+		{"INT_マイナスの場合は0の方向に切り捨てる", `INT(VAL("-2.7"))`, -2},
+		{"INT_整数はそのまま返す", `INT(3)`, 3},
+		{"CEIL_小数点以下を切り上げる", `CEIL(VAL("2.1"))`, 3},
+		{"CEIL_マイナスの場合は0の方向に切り上げる", `CEIL(VAL("-2.7"))`, -2},
+		{"ROUND_整数に四捨五入する", `ROUND(VAL("2.5"))`, 3},
+		{"ROUND_マイナスの数値は0から離れる方向に四捨五入する", `ROUND(VAL("-2.5"))`, -3},
+		{"ROUND_桁がマイナスの場合は小数点以下の桁で四捨五入する", `ROUND(VAL("1.2345"), -2)`, 1.23},
+		{"ROUND_桁がプラスの場合は整数部の桁で四捨五入する", `ROUND(1250, 2)`, 1300},
+		{"ROUND_小数点以下の桁が大きすぎる場合はそのまま返す", `ROUND(VAL("1.5"), -400)`, 1.5},
+		{"ROUND_0で小数点以下の桁が大きすぎる場合は0を返す", `ROUND(0, -400)`, 0},
+		{"ROUND_整数部の桁が大きすぎる場合は0を返す", `ROUND(1250, 400)`, 0},
+		{"SQRT_平方根を返す", `SQRT(16)`, 4},
+		{"SQRT_割り切れない場合は実数を返す", `SQRT(2)`, math.Sqrt2},
+		{"SQRT_マイナスの場合はエラーになる", `SQRT(-1)`, &object.Error{Message: "domain error in `SQRT`: -1"}},
+		{"POWER_累乗を返す", `POWER(2, 10)`, 1024},
+		{"POWER_指数がマイナスの場合は実数を返す", `POWER(2, -1)`, 0.5},
+		{"POWER_マイナスの小数乗はエラーになる", `POWER(-8, VAL("0.5"))`, &object.Error{Message: "domain error in `POWER`: -8, 0.5"}},
+		{"EXP_自然対数の底の累乗を返す", `EXP(1)`, math.E},
+		{"EXP_0乗は1を返す", `EXP(0)`, 1},
+		{"EXP_桁あふれする場合はエラーになる", `EXP(1000)`, &object.Error{Message: "domain error in `EXP`: 1000"}},
+		{"LOGN_自然対数を返す", `LOGN(1)`, 0},
+		{"LOGN_0以下の場合はエラーになる", `LOGN(0)`, &object.Error{Message: "domain error in `LOGN`: 0"}},
+		{"SIN_正弦を返す", `SIN(0)`, 0},
+		{"COS_余弦を返す", `COS(0)`, 1},
+		{"TAN_正接を返す", `TAN(1)`, math.Tan(1)},
+		{"ARCTAN_逆正接を返す", `ARCTAN(1)`, math.Pi / 4},
+		{"ZCUT_マイナスの場合は0を返す", `ZCUT(-5)`, 0},
+		{"ZCUT_プラスの場合はそのまま返す", `ZCUT(5)`, 5},
+		{"RANDOM_上限が0以下の場合はエラーになる", `RANDOM(0)`, &object.Error{Message: "argument 1 to `RANDOM` should be positive, got 0"}},
+		{"数値に変換できない引数はエラーになる", `ABS("a")`, &object.Error{Message: "argument 1 to `ABS` not supported, got STRING"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testExpectedObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
+
+func TestRandom(t *testing.T) {
+	input := `RANDOM(10) * 100 + RANDOM(10)`
+
+	env := object.NewEnvironment()
+	env.SeedRandom(1)
+	first, ok := testEvalEnv(env, input).(*object.Integer)
+	if !ok {
+		t.Fatalf("object is not Integer. got=%T", first)
+	}
+	if first.Value < 0 || first.Value > 909 || first.Value%100 > 9 {
+		t.Errorf("RANDOM returned out of range value. got=%d", first.Value)
+	}
+
+	// 同じ種を設定すると同じ結果になる
+	env = object.NewEnvironment()
+	env.SeedRandom(1)
+	testIntegerObject(t, testEvalEnv(env, input), first.Value)
+}
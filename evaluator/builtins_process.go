@@ -0,0 +1,175 @@
+package evaluator
+
+import (
+	"os"
+	"strings"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+var processBuiltinFunctions = map[string]*object.BuiltinFunction{
+	// ENV(名前) 環境変数の値を返す。存在しない場合は空文字を返す
+	"ENV": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 1 {
+				return wrongNumberOfArguments("ENV", len(args), "1")
+			}
+			name, err := stringArgument("ENV", args, 0)
+			if err != nil {
+				return err
+			}
+			return returnResult(&object.String{Value: os.Getenv(name)})
+		},
+	},
+	// SETENV(名前, [値]) 値を省略した場合は環境変数を削除する
+	// NOTE: 実行できるコマンドを制限している場合は、別のコマンドに差し替えられないようにPATHを変更できない
+	"SETENV": {
+		EnvFn: func(env *object.Environment, args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return wrongNumberOfArguments("SETENV", len(args), "1 or 2")
+			}
+			name, err := stringArgument("SETENV", args, 0)
+			if err != nil {
+				return err
+			}
+			if restrictsCommands(env) && strings.EqualFold(name, "PATH") {
+				return newError("cannot change PATH in `SETENV` while commands are restricted")
+			}
+			if isOmitted(args, 1) {
+				return returnResult(nativeBoolToBooleanObject(os.Unsetenv(name) == nil))
+			}
+			return returnResult(nativeBoolToBooleanObject(os.Setenv(name, toStringValue(args[1].Value)) == nil))
+		},
+	},
+	// EXEC(コマンド, [同期=TRUE], [作業ディレクトリ], [タイムアウト秒], [var 出力])
+	// 同期の場合は終了コード、非同期の場合はプロセスIDを返す。標準出力と標準エラー出力は出力の変数に書き戻す
+	// NOTE: コマンドはシェルを通さずに実行する。タイムアウトした場合は終了コードを-1にする
+	// 非同期の場合もタイムアウトやスクリプトの中断で起動したコマンドを止める
+	"EXEC": {
+		EnvFn: func(env *object.Environment, args ...object.BuiltinFuncArgument) object.Object {
+			ctx := env.Context()
+			if len(args) < 1 || len(args) > 5 {
+				return wrongNumberOfArguments("EXEC", len(args), "1 to 5")
+			}
+			command, err := stringArgument("EXEC", args, 0)
+			if err != nil {
+				return err
+			}
+			argv := splitCommandLine(command)
+			if len(argv) == 0 {
+				return newError("command to `EXEC` should not be empty")
+			}
+			wait, err := boolArgument("EXEC", args, 1, true)
+			if err != nil {
+				return err
+			}
+			dir, err := commandDirArgument("EXEC", args, 2)
+			if err != nil {
+				return err
+			}
+			timeout, err := commandTimeoutArgument("EXEC", args, 3)
+			if err != nil {
+				return err
+			}
+			path, allowed := allowsCommand(env, argv[0])
+			if !allowed {
+				return newError("command not allowed in `EXEC`: %s", argv[0])
+			}
+			argv[0] = path
+
+			if !wait {
+				pid, startErr := startCommand(ctx, argv, dir, timeout)
+				if startErr != nil {
+					return newError("failed to run command in `EXEC`: %s", startErr)
+				}
+				return commandResultWithOutput(args, 4, "", &object.Integer{Value: int64(pid)})
+			}
+			result, runErr := runCommand(ctx, argv, dir, timeout)
+			if ctx.Err() != nil {
+				return interruptedError(ctx.Err())
+			}
+			if runErr != nil {
+				return newError("failed to run command in `EXEC`: %s", runErr)
+			}
+			return commandResultWithOutput(args, 4, result.output, &object.Integer{Value: int64(result.exitCode)})
+		},
+	},
+	// DOSCMD(コマンド, [非同期=FALSE], [作業ディレクトリ], [タイムアウト秒])
+	// シェルでコマンドを実行し、標準出力と標準エラー出力を返す。非同期の場合は空文字を返す
+	"DOSCMD": {
+		EnvFn: func(env *object.Environment, args ...object.BuiltinFuncArgument) object.Object {
+			ctx := env.Context()
+			if len(args) < 1 || len(args) > 4 {
+				return wrongNumberOfArguments("DOSCMD", len(args), "1 to 4")
+			}
+			command, err := stringArgument("DOSCMD", args, 0)
+			if err != nil {
+				return err
+			}
+			async, err := boolArgument("DOSCMD", args, 1, false)
+			if err != nil {
+				return err
+			}
+			dir, err := commandDirArgument("DOSCMD", args, 2)
+			if err != nil {
+				return err
+			}
+			timeout, err := commandTimeoutArgument("DOSCMD", args, 3)
+			if err != nil {
+				return err
+			}
+			// NOTE: シェルに渡すコマンドは確認できないため、制限している場合は実行しない
+			if restrictsCommands(env) {
+				return newError("`DOSCMD` is not allowed while commands are restricted")
+			}
+			argv := shellCommand(command)
+
+			if async {
+				if _, startErr := startCommand(ctx, argv, dir, timeout); startErr != nil {
+					return newError("failed to run command in `DOSCMD`: %s", startErr)
+				}
+				return returnResult(&object.String{Value: ""})
+			}
+			result, runErr := runCommand(ctx, argv, dir, timeout)
+			if ctx.Err() != nil {
+				return interruptedError(ctx.Err())
+			}
+			if runErr != nil {
+				return newError("failed to run command in `DOSCMD`: %s", runErr)
+			}
+			return returnResult(&object.String{Value: result.output})
+		},
+	},
+}
+
+// 省略された場合はカレントディレクトリで実行する
+func commandDirArgument(name string, args []object.BuiltinFuncArgument, i int) (string, *object.Error) {
+	if isOmitted(args, i) {
+		return "", nil
+	}
+	return stringArgument(name, args, i)
+}
+
+// 省略された場合や0以下の場合はタイムアウトしない
+func commandTimeoutArgument(name string, args []object.BuiltinFuncArgument, i int) (float64, *object.Error) {
+	if isOmitted(args, i) {
+		return 0, nil
+	}
+	num, err := numberArgument(name, args, i)
+	if err != nil {
+		return 0, err
+	}
+	return toFloat(num), nil
+}
+
+// 出力の変数が指定されている場合は書き戻す
+func commandResultWithOutput(args []object.BuiltinFuncArgument, i int, output string, result object.Object) object.Object {
+	if isOmitted(args, i) {
+		return returnResult(result)
+	}
+	return &object.BuiltinFuncReturnReference{
+		Expression: args[i].Expression,
+		Value:      &object.String{Value: output},
+		Result:     result,
+	}
+}
@@ -0,0 +1,159 @@
+package evaluator_test
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sam8helloworld/uwscgo/evaluator"
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+func TestEnvBuiltinFunctions(t *testing.T) {
+	t.Setenv("UWSCGO_TEST", "value")
+
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"ENV_環境変数の値を返す", `ENV("UWSCGO_TEST")`, "value"},
+		{"ENV_存在しない場合は空文字を返す", `ENV("UWSCGO_NONE")`, ""},
+		{"SETENV_環境変数を設定する", `SETENV("UWSCGO_TEST", 10)
+ENV("UWSCGO_TEST")`, "10"},
+		{"SETENV_値を省略すると削除する", `SETENV("UWSCGO_TEST")
+ENV("UWSCGO_TEST")`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("UWSCGO_TEST", "value")
+			testExpectedObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
+
+func TestProcessBuiltinFunctions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is required")
+	}
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"EXEC_終了コードを返す", `EXEC("sh -c 'exit 3'")`, 3},
+		{"EXEC_出力を変数に書き戻す", `out = ""
+EXEC("echo hello world", TRUE, , , out)
+out`, "hello world\n"},
+		{"EXEC_標準エラー出力も書き戻す", `out = ""
+EXEC("sh -c 'echo error >&2'", TRUE, , , out)
+out`, "error\n"},
+		{"EXEC_作業ディレクトリを指定する", `out = ""
+EXEC("pwd", TRUE, "` + dir + `", , out)
+out`, dir + "\n"},
+		{"EXEC_タイムアウトした場合は-1を返す", `EXEC("sleep 10", TRUE, , VAL("0.1"))`, -1},
+		{"EXEC_非同期の場合はプロセスIDを返す", `0 < EXEC("sleep 0", FALSE)`, true},
+		{"EXEC_存在しないコマンドはエラーになる", `EXEC("uwscgo-none")`, &object.Error{Message: "failed to run command in `EXEC`: exec: \"uwscgo-none\": executable file not found in $PATH"}},
+		{"EXEC_空のコマンドはエラーになる", `EXEC("")`, &object.Error{Message: "command to `EXEC` should not be empty"}},
+		{"DOSCMD_シェルで実行して出力を返す", `DOSCMD("echo a; echo b")`, "a\nb\n"},
+		{"DOSCMD_作業ディレクトリを指定する", `DOSCMD("pwd", FALSE, "` + dir + `")`, dir + "\n"},
+		{"DOSCMD_タイムアウトした場合はそれまでの出力を返す", `DOSCMD("echo a; sleep 10", FALSE, , VAL("0.1"))`, "a\n"},
+		{"DOSCMD_非同期の場合は空文字を返す", `DOSCMD("sleep 0", TRUE)`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testExpectedObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
+
+func TestSetAllowedCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is required")
+	}
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"EXEC_許可されたコマンドは実行できる", `EXEC("echo a")`, 0},
+		{"EXEC_許可されていないコマンドはエラーになる", `EXEC("sh -c 'exit 0'")`, &object.Error{Message: "command not allowed in `EXEC`: sh"}},
+		{"EXEC_同じ名前の別のコマンドはエラーになる", `COPYFILE("/bin/sh", "` + dir + `/echo")
+EXEC("` + dir + `/echo -c 'exit 0'")`, &object.Error{Message: "command not allowed in `EXEC`: " + dir + "/echo"}},
+		{"SETENV_PATHは変更できない", `SETENV("PATH", "` + dir + `")`, &object.Error{Message: "cannot change PATH in `SETENV` while commands are restricted"}},
+		{"SETENV_PATH以外は変更できる", `SETENV("UWSCGO_TEST", "a")`, true},
+		{"DOSCMD_制限している場合はエラーになる", `DOSCMD("echo a")`, &object.Error{Message: "`DOSCMD` is not allowed while commands are restricted"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("UWSCGO_TEST", "")
+			env := object.NewEnvironment()
+			if err := evaluator.SetAllowedCommands(env, []string{"echo"}); err != nil {
+				t.Fatal(err)
+			}
+			testExpectedObject(t, testEvalEnv(env, tt.input), tt.expected)
+		})
+	}
+}
+
+func TestSetAllowedCommands_PATHを差し替えても許可したコマンドだけを実行する(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is required")
+	}
+	env := object.NewEnvironment()
+	if err := evaluator.SetAllowedCommands(env, []string{"echo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// NOTE: スクリプトの外でPATHを変更された場合も、許可した時点の絶対パスで比較する
+	dir := t.TempDir()
+	testEvalEnv(env, `COPYFILE("/bin/sh", "`+dir+`/echo")`)
+	t.Setenv("PATH", dir)
+	testExpectedObject(t, testEvalEnv(env, `EXEC("echo -c 'echo pwned'")`), &object.Error{Message: "command not allowed in `EXEC`: echo"})
+}
+
+func TestSetAllowedCommands_内側の環境を作った後に設定しても制限する(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is required")
+	}
+	env := object.NewEnvironment()
+	inner := object.NewEnclosedEnvironment(env)
+	if err := evaluator.SetAllowedCommands(env, []string{"echo"}); err != nil {
+		t.Fatal(err)
+	}
+	testExpectedObject(t, testEvalEnv(inner, `EXEC("sh -c 'exit 0'")`), &object.Error{Message: "command not allowed in `EXEC`: sh"})
+}
+
+func TestExec_非同期のコマンドはタイムアウトで止める(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is required")
+	}
+	dir := t.TempDir()
+	testEval(`EXEC("sh -c 'sleep 0.3; echo done > ` + dir + `/out.txt'", FALSE, , VAL("0.1"))`)
+	time.Sleep(600 * time.Millisecond)
+	if _, err := os.Stat(dir + "/out.txt"); !os.IsNotExist(err) {
+		t.Errorf("async command was not stopped by timeout. err=%v", err)
+	}
+}
+
+func TestExec_キャンセルされるとコマンドを止める(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep is required")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	testExpectedObject(t, testEvalContext(ctx, `EXEC("sleep 10")`), &object.Error{Message: "script interrupted: context deadline exceeded"})
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("command was not interrupted. elapsed=%s", elapsed)
+	}
+}
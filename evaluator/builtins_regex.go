@@ -0,0 +1,116 @@
+package evaluator
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+// 正規表現はGoのregexp(RE2)で処理するため、VBScriptの正規表現とは次の点が異なる
+//   - 先読み・後読み((?=...)、(?!...)など)と後方参照(\1など)は使えない
+//   - 大文字小文字を区別しない場合は引数で指定するか、(?i)を先頭に付ける
+//   - 置換文字列の$1、$&はVBScriptと同じ意味になるように変換する
+var regexBuiltinFunctions = map[string]*object.BuiltinFunction{
+	// TESTREGEX(文字列, 正規表現, [大文字小文字を区別しない])
+	"TESTREGEX": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 2 || len(args) > 3 {
+				return wrongNumberOfArguments("TESTREGEX", len(args), "2 or 3")
+			}
+			str, err := stringArgument("TESTREGEX", args, 0)
+			if err != nil {
+				return err
+			}
+			re, err := regexArgument("TESTREGEX", args, 1, 2)
+			if err != nil {
+				return err
+			}
+			return returnResult(nativeBoolToBooleanObject(re.MatchString(str)))
+		},
+	},
+	// MATCH(文字列, 正規表現, [サブマッチ番号], [大文字小文字を区別しない])
+	// マッチした全ての文字列を配列で返す。サブマッチ番号を指定した場合はそのグループの文字列を返す
+	"MATCH": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 2 || len(args) > 4 {
+				return wrongNumberOfArguments("MATCH", len(args), "2 to 4")
+			}
+			str, err := stringArgument("MATCH", args, 0)
+			if err != nil {
+				return err
+			}
+			re, err := regexArgument("MATCH", args, 1, 3)
+			if err != nil {
+				return err
+			}
+			group, err := integerArgument("MATCH", args, 2, 0)
+			if err != nil {
+				return err
+			}
+			if group < 0 || group > int64(re.NumSubexp()) {
+				return newError("submatch %d does not exist in `MATCH`. pattern=%q", group, re.String())
+			}
+			elements := []object.Object{}
+			for _, m := range re.FindAllStringSubmatch(str, -1) {
+				elements = append(elements, &object.String{Value: m[group]})
+			}
+			return returnResult(&object.Array{Elements: elements})
+		},
+	},
+}
+
+// 正規表現の引数をコンパイルする。ignoreCaseIndexの引数がTRUEの場合は大文字小文字を区別しない
+func regexArgument(name string, args []object.BuiltinFuncArgument, i, ignoreCaseIndex int) (*regexp.Regexp, *object.Error) {
+	pattern, err := stringArgument(name, args, i)
+	if err != nil {
+		return nil, err
+	}
+	ignoreCase, err := boolArgument(name, args, ignoreCaseIndex, false)
+	if err != nil {
+		return nil, err
+	}
+	return compileRegex(name, pattern, ignoreCase)
+}
+
+func compileRegex(name, pattern string, ignoreCase bool) (*regexp.Regexp, *object.Error) {
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, newError("invalid regular expression in `%s`: %s", name, err)
+	}
+	return re, nil
+}
+
+// VBScriptの置換文字列をGoの形式に変換して置換する
+// NOTE: $N と $& 以外の $ は文字として扱う。$$ は $ 1文字になる
+func replaceRegex(str string, re *regexp.Regexp, replacement string) string {
+	var template strings.Builder
+	for i := 0; i < len(replacement); i++ {
+		if replacement[i] != '$' {
+			template.WriteByte(replacement[i])
+			continue
+		}
+		j := i + 1
+		for j < len(replacement) && '0' <= replacement[j] && replacement[j] <= '9' {
+			j++
+		}
+		switch {
+		case j > i+1:
+			template.WriteString("${" + replacement[i+1:j] + "}")
+			i = j - 1
+		case strings.HasPrefix(replacement[i+1:], "&"):
+			template.WriteString("${0}")
+			i++
+		case strings.HasPrefix(replacement[i+1:], "$"):
+			template.WriteString("$$")
+			i++
+		default:
+			template.WriteString("$$")
+		}
+	}
+	replacement = template.String()
+	return re.ReplaceAllString(str, replacement)
+}
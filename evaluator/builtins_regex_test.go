@@ -0,0 +1,43 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+func TestRegexBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"TESTREGEX_マッチする場合はTRUEを返す", `TESTREGEX("ERROR 404", "^ERROR \d+$")`, true},
+		{"TESTREGEX_マッチしない場合はFALSEを返す", `TESTREGEX("INFO 200", "^ERROR")`, false},
+		{"TESTREGEX_大文字小文字を区別する", `TESTREGEX("error", "ERROR")`, false},
+		{"TESTREGEX_第3引数がTRUEの場合は大文字小文字を区別しない", `TESTREGEX("error", "ERROR", TRUE)`, true},
+		{"TESTREGEX_(?i)で大文字小文字を区別しない", `TESTREGEX("error", "(?i)ERROR")`, true},
+		{"TESTREGEX_不正な正規表現はエラーになる", `TESTREGEX("a", "(")`, &object.Error{Message: "invalid regular expression in `TESTREGEX`: error parsing regexp: missing closing ): `(`"}},
+		{"MATCH_マッチした数を返す", `LENGTH(MATCH("a1 b22 c333", "\d+"))`, 3},
+		{"MATCH_マッチした文字列を配列で返す", `JOIN(MATCH("a1 b22 c333", "\d+"), ",")`, "1,22,333"},
+		{"MATCH_マッチしない場合は空の配列を返す", `LENGTH(MATCH("abc", "\d+"))`, 0},
+		{"MATCH_サブマッチ番号を指定した場合はそのグループを返す", `JOIN(MATCH("k1=v1;k2=v2", "(\w+)=(\w+)", 2), ",")`, "v1,v2"},
+		{"MATCH_大文字小文字を区別しない", `JOIN(MATCH("Ab aB", "ab", 0, TRUE), ",")`, "Ab,aB"},
+		{"MATCH_存在しないサブマッチ番号はエラーになる", `MATCH("a", "(a)", 2)`, &object.Error{Message: "submatch 2 does not exist in `MATCH`. pattern=\"(a)\""}},
+		{"REPLACE_第4引数がTRUEの場合は正規表現で置換する", `REPLACE("a1b22", "\d+", "#", TRUE)`, "a#b#"},
+		{"REPLACE_正規表現で置換する場合は大文字小文字を区別する", `REPLACE("Aa", "a", "x", TRUE)`, "Ax"},
+		{"REPLACE_置換文字列の$1はサブマッチに置き換える", `REPLACE("2021-03-04", "(\d+)-(\d+)-(\d+)", "$3/$2/$1", TRUE)`, "04/03/2021"},
+		{"REPLACE_$1の直後の文字は名前に含めない", `REPLACE("ab", "(a)", "$1x", TRUE)`, "axb"},
+		{"REPLACE_置換文字列の$&はマッチした文字列に置き換える", `REPLACE("abc", "b", "[$&]", TRUE)`, "a[b]c"},
+		{"REPLACE_$の後に数字がない場合は文字として扱う", `REPLACE("price", "price", "$USD", TRUE)`, "$USD"},
+		{"REPLACE_末尾の$は文字として扱う", `REPLACE("a", "a", "a$", TRUE)`, "a$"},
+		{"REPLACE_$$は$1文字にする", `REPLACE("ab", "(a)", "$$1", TRUE)`, "$1b"},
+		{"REPLACE_${1}は文字として扱う", `REPLACE("ab", "(a)", "${1}", TRUE)`, "${1}b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testExpectedObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
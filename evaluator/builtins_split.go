@@ -0,0 +1,269 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+var splitBuiltinFunctions = map[string]*object.BuiltinFunction{
+	// BETWEENSTR(文字列, [前文字列], [後文字列], [n番目], [取得方法])
+	// 取得方法がTRUEの場合は前文字列から最も遠い後文字列までを返す
+	"BETWEENSTR": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 5 {
+				return wrongNumberOfArguments("BETWEENSTR", len(args), "1 to 5")
+			}
+			str, err := stringArgument("BETWEENSTR", args, 0)
+			if err != nil {
+				return err
+			}
+			before, after := "", ""
+			if !isOmitted(args, 1) {
+				if before, err = stringArgument("BETWEENSTR", args, 1); err != nil {
+					return err
+				}
+			}
+			if !isOmitted(args, 2) {
+				if after, err = stringArgument("BETWEENSTR", args, 2); err != nil {
+					return err
+				}
+			}
+			nth, err := integerArgument("BETWEENSTR", args, 3, 1)
+			if err != nil {
+				return err
+			}
+			longest, err := boolArgument("BETWEENSTR", args, 4, false)
+			if err != nil {
+				return err
+			}
+			return returnResult(&object.String{Value: betweenStr(str, before, after, int(nth), longest)})
+		},
+	},
+	// TOKEN(区切り文字, var 文字列, [区切り方法], [ダブルクォートを考慮])
+	// 切り出した残りを第2引数の変数に書き戻す
+	"TOKEN": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 2 || len(args) > 4 {
+				return wrongNumberOfArguments("TOKEN", len(args), "2 to 4")
+			}
+			delimiters, err := stringArgument("TOKEN", args, 0)
+			if err != nil {
+				return err
+			}
+			str, err := stringArgument("TOKEN", args, 1)
+			if err != nil {
+				return err
+			}
+			// NOTE: FALSEの場合は連続した区切り文字を1つの区切りとして扱う
+			eachDelimiter, err := boolArgument("TOKEN", args, 2, false)
+			if err != nil {
+				return err
+			}
+			quoted, err := boolArgument("TOKEN", args, 3, false)
+			if err != nil {
+				return err
+			}
+			tok, rest := token(str, delimiters, eachDelimiter, quoted)
+			return &object.BuiltinFuncReturnReference{
+				Expression: args[1].Expression,
+				Value:      &object.String{Value: rest},
+				Result:     &object.String{Value: tok},
+			}
+		},
+	},
+	// SPLIT(文字列, [区切り文字列], [空文字除去], [数値変換])
+	"SPLIT": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 4 {
+				return wrongNumberOfArguments("SPLIT", len(args), "1 to 4")
+			}
+			str, err := stringArgument("SPLIT", args, 0)
+			if err != nil {
+				return err
+			}
+			sep := " "
+			if !isOmitted(args, 1) {
+				if sep, err = stringArgument("SPLIT", args, 1); err != nil {
+					return err
+				}
+			}
+			skipEmpty, err := boolArgument("SPLIT", args, 2, false)
+			if err != nil {
+				return err
+			}
+			toNumber, err := boolArgument("SPLIT", args, 3, false)
+			if err != nil {
+				return err
+			}
+
+			var fields []string
+			if sep == "" {
+				fields = []string{str}
+			} else {
+				fields = strings.Split(str, sep)
+			}
+			elements := []object.Object{}
+			for _, f := range fields {
+				if skipEmpty && f == "" {
+					continue
+				}
+				if !toNumber {
+					elements = append(elements, &object.String{Value: f})
+					continue
+				}
+				num, ok := parseNumber(f)
+				if !ok {
+					return newError("cannot convert %q to number in `SPLIT`", f)
+				}
+				elements = append(elements, num)
+			}
+			return returnResult(&object.Array{Elements: elements})
+		},
+	},
+	// JOIN(配列, [区切り文字列], [空文字除外], [開始], [終了])
+	"JOIN": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 1 || len(args) > 5 {
+				return wrongNumberOfArguments("JOIN", len(args), "1 to 5")
+			}
+			array, ok := args[0].Value.(*object.Array)
+			if !ok {
+				return newError("argument 1 to `JOIN` not supported, got %s", args[0].Value.Type())
+			}
+			sep := " "
+			var err *object.Error
+			if !isOmitted(args, 1) {
+				if sep, err = stringArgument("JOIN", args, 1); err != nil {
+					return err
+				}
+			}
+			skipEmpty, err := boolArgument("JOIN", args, 2, false)
+			if err != nil {
+				return err
+			}
+			from, err := integerArgument("JOIN", args, 3, 0)
+			if err != nil {
+				return err
+			}
+			to, err := integerArgument("JOIN", args, 4, int64(len(array.Elements)-1))
+			if err != nil {
+				return err
+			}
+			// NOTE: 一致しなかったMATCHの結果のような空の配列は空文字にする
+			if len(array.Elements) == 0 {
+				return returnResult(&object.String{Value: ""})
+			}
+			if from < 0 || from >= int64(len(array.Elements)) {
+				return newError("index out of range in `JOIN`. from=%d, length=%d", from, len(array.Elements))
+			}
+			if to < from || to >= int64(len(array.Elements)) {
+				return newError("index out of range in `JOIN`. to=%d, length=%d", to, len(array.Elements))
+			}
+
+			values := []string{}
+			for _, e := range array.Elements[from : to+1] {
+				v := ""
+				if e != EMPTY {
+					v = e.Inspect()
+				}
+				if skipEmpty && v == "" {
+					continue
+				}
+				values = append(values, v)
+			}
+			return returnResult(&object.String{Value: strings.Join(values, sep)})
+		},
+	},
+}
+
+// nth番目(マイナスの場合は後ろから数える)の前文字列と後文字列に挟まれた文字列を返す
+func betweenStr(str, before, after string, nth int, longest bool) string {
+	if nth == 0 {
+		return ""
+	}
+	// NOTE: 前文字列が省略された場合はn番目の後文字列の手前までを返す
+	if before == "" {
+		if after == "" {
+			return str
+		}
+		i, ok := nthIndex(str, after, nth)
+		if !ok {
+			return ""
+		}
+		return str[:i]
+	}
+
+	starts := []int{}
+	for offset := 0; ; {
+		i := strings.Index(str[offset:], before)
+		if i < 0 {
+			break
+		}
+		starts = append(starts, offset+i+len(before))
+		offset += i + len(before)
+	}
+	if nth < 0 {
+		nth = len(starts) + nth + 1
+	}
+	if nth < 1 || nth > len(starts) {
+		return ""
+	}
+	start := starts[nth-1]
+	if after == "" {
+		return str[start:]
+	}
+	var end int
+	if longest {
+		end = strings.LastIndex(str[start:], after)
+	} else {
+		end = strings.Index(str[start:], after)
+	}
+	if end < 0 {
+		return ""
+	}
+	return str[start : start+end]
+}
+
+// nth番目(マイナスの場合は後ろから数える)に見つかったsubstrの位置を返す
+func nthIndex(str, substr string, nth int) (int, bool) {
+	indexes := []int{}
+	for offset := 0; ; {
+		i := strings.Index(str[offset:], substr)
+		if i < 0 {
+			break
+		}
+		indexes = append(indexes, offset+i)
+		offset += i + len(substr)
+	}
+	if nth < 0 {
+		nth = len(indexes) + nth + 1
+	}
+	if nth < 1 || nth > len(indexes) {
+		return 0, false
+	}
+	return indexes[nth-1], true
+}
+
+// 最初の区切り文字までの文字列と残りの文字列を返す
+func token(str, delimiters string, eachDelimiter, quoted bool) (string, string) {
+	runes := []rune(str)
+	inQuote := false
+	for i, r := range runes {
+		if quoted && r == '"' {
+			inQuote = !inQuote
+			continue
+		}
+		if inQuote || !strings.ContainsRune(delimiters, r) {
+			continue
+		}
+		rest := i + 1
+		if !eachDelimiter {
+			for rest < len(runes) && strings.ContainsRune(delimiters, runes[rest]) {
+				rest++
+			}
+		}
+		return string(runes[:i]), string(runes[rest:])
+	}
+	return str, ""
+}
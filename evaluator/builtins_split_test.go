@@ -0,0 +1,116 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+func TestSplitBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"BETWEENSTR_前文字列と後文字列に挟まれた文字列を返す", `BETWEENSTR("[a][b][c]", "[", "]")`, "a"},
+		{"BETWEENSTR_n番目に挟まれた文字列を返す", `BETWEENSTR("[a][b][c]", "[", "]", 2)`, "b"},
+		{"BETWEENSTR_n番目がマイナスの場合は後ろから数える", `BETWEENSTR("[a][b][c]", "[", "]", -1)`, "c"},
+		{"BETWEENSTR_前文字列を省略した場合は先頭から返す", `BETWEENSTR("key=value=x", , "=")`, "key"},
+		{"BETWEENSTR_前文字列を省略した場合はn番目の後文字列までを返す", `BETWEENSTR("key=value=x", , "=", 2)`, "key=value"},
+		{"BETWEENSTR_後文字列を省略した場合は最後まで返す", `BETWEENSTR("key=value=x", "=")`, "value=x"},
+		{"BETWEENSTR_取得方法がTRUEの場合は最も遠い後文字列までを返す", `BETWEENSTR("(a(b)c)", "(", ")", 1, TRUE)`, "a(b)c"},
+		{"BETWEENSTR_見つからない場合は空文字を返す", `BETWEENSTR("abc", "[", "]")`, ""},
+		{"BETWEENSTR_日本語を扱える", `BETWEENSTR("【重要】お知らせ", "【", "】")`, "重要"},
+		{
+			"TOKEN_最初の区切り文字までを返す",
+			`s = "a,b,c"
+TOKEN(",", s)`,
+			"a",
+		},
+		{
+			"TOKEN_残りの文字列を変数に書き戻す",
+			`s = "a,b,c"
+TOKEN(",", s)
+s`,
+			"b,c",
+		},
+		{
+			"TOKEN_連続した区切り文字を1つとして扱う",
+			`s = "a, ,b"
+TOKEN(", ", s)
+s`,
+			"b",
+		},
+		{
+			"TOKEN_区切り方法がTRUEの場合は区切り文字毎に区切る",
+			`s = "a,,b"
+TOKEN(",", s, TRUE)
+TOKEN(",", s, TRUE)`,
+			"",
+		},
+		{
+			"TOKEN_区切り文字がない場合は全てを返し変数を空にする",
+			`s = "abc"
+TOKEN(",", s) + s`,
+			"abc",
+		},
+		{"SPLIT_区切り文字で分割する", `LENGTH(SPLIT("a,b,,c", ","))`, 4},
+		{"SPLIT_区切り文字を省略した場合は空白で分割する", `SPLIT("a b c")[1]`, "b"},
+		{"SPLIT_空文字除去がTRUEの場合は空の要素を除く", `LENGTH(SPLIT("a,b,,c", ",", TRUE))`, 3},
+		{
+			"SPLIT_数値変換がTRUEの場合は数値に変換する",
+			`a = SPLIT("1,2", ",", FALSE, TRUE)
+a[0] + a[1]`,
+			3,
+		},
+		{"SPLIT_数値変換がTRUEの場合は小数も変換する", `SPLIT("1.5", ",", FALSE, TRUE)[0]`, 1.5},
+		{"SPLIT_数値に変換できない場合はエラーになる", `SPLIT("1,a", ",", FALSE, TRUE)`, &object.Error{Message: "cannot convert \"a\" to number in `SPLIT`"}},
+		{
+			"JOIN_区切り文字で連結する",
+			`DIM array[] = "a", "b", "c"
+JOIN(array, "-")`,
+			"a-b-c",
+		},
+		{
+			"JOIN_区切り文字を省略した場合は空白で連結する",
+			`DIM array[] = 1, 2, 3
+JOIN(array)`,
+			"1 2 3",
+		},
+		{
+			"JOIN_空文字除外がTRUEの場合は空の要素を除く",
+			`DIM array[] = "a", "", "c"
+JOIN(array, ",", TRUE)`,
+			"a,c",
+		},
+		{
+			"JOIN_開始と終了の範囲を連結する",
+			`DIM array[] = "a", "b", "c", "d"
+JOIN(array, ",", FALSE, 1, 2)`,
+			"b,c",
+		},
+		{
+			"JOIN_終了を省略した場合は最後まで連結する",
+			`DIM array[] = "a", "b", "c", "d"
+JOIN(array, ",", FALSE, 2)`,
+			"c,d",
+		},
+		{
+			"JOIN_範囲外の場合はエラーになる",
+			`DIM array[] = "a", "b"
+JOIN(array, ",", FALSE, 0, 5)`,
+			&object.Error{Message: "index out of range in `JOIN`. to=5, length=2"},
+		},
+		{
+			"JOIN_空の配列は空文字を返す",
+			`JOIN(MATCH("abc", "\d"), ",")`,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testExpectedObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
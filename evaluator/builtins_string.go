@@ -0,0 +1,414 @@
+package evaluator
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+var stringBuiltinFunctions = map[string]*object.BuiltinFunction{
+	// COPY(文字列, 開始位置, [文字数])
+	"COPY": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 2 || len(args) > 3 {
+				return wrongNumberOfArguments("COPY", len(args), "2 or 3")
+			}
+			str, err := stringArgument("COPY", args, 0)
+			if err != nil {
+				return err
+			}
+			start, err := integerArgument("COPY", args, 1, 1)
+			if err != nil {
+				return err
+			}
+			runes := []rune(str)
+			// NOTE: 開始位置がマイナスの場合は後ろから数える
+			if start < 0 {
+				start = int64(len(runes)) + start + 1
+			}
+			if start < 1 {
+				start = 1
+			}
+			if start > int64(len(runes)) {
+				return returnResult(&object.String{Value: ""})
+			}
+			length, err := integerArgument("COPY", args, 2, int64(len(runes)))
+			if err != nil {
+				return err
+			}
+			end := start - 1 + length
+			if length < 0 || end > int64(len(runes)) {
+				end = int64(len(runes))
+			}
+			return returnResult(&object.String{Value: string(runes[start-1 : end])})
+		},
+	},
+	// POS(探す文字列, 文字列, [n番目]) 見つからない場合は0を返す
+	"POS": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 2 || len(args) > 3 {
+				return wrongNumberOfArguments("POS", len(args), "2 or 3")
+			}
+			substr, err := stringArgument("POS", args, 0)
+			if err != nil {
+				return err
+			}
+			str, err := stringArgument("POS", args, 1)
+			if err != nil {
+				return err
+			}
+			nth, err := integerArgument("POS", args, 2, 1)
+			if err != nil {
+				return err
+			}
+			positions := indexAllFold([]rune(str), []rune(substr))
+			// NOTE: マイナスの場合は後ろから探す
+			if nth < 0 {
+				nth = int64(len(positions)) + nth + 1
+			}
+			if nth < 1 || nth > int64(len(positions)) {
+				return returnResult(&object.Integer{Value: 0})
+			}
+			return returnResult(&object.Integer{Value: int64(positions[nth-1] + 1)})
+		},
+	},
+	// REPLACE(文字列, 置換対象, 置換文字列, [正規表現], [大文字小文字を区別])
+	// 正規表現がTRUEの場合は置換対象を正規表現として扱う
+	// NOTE: 大文字小文字を区別するかを省略した場合、通常の置換は区別せず、正規表現の置換は区別する
+	"REPLACE": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) < 3 || len(args) > 5 {
+				return wrongNumberOfArguments("REPLACE", len(args), "3 to 5")
+			}
+			str, err := stringArgument("REPLACE", args, 0)
+			if err != nil {
+				return err
+			}
+			old, err := stringArgument("REPLACE", args, 1)
+			if err != nil {
+				return err
+			}
+			replacement, err := stringArgument("REPLACE", args, 2)
+			if err != nil {
+				return err
+			}
+			useRegex, err := boolArgument("REPLACE", args, 3, false)
+			if err != nil {
+				return err
+			}
+			caseSensitive, err := boolArgument("REPLACE", args, 4, useRegex)
+			if err != nil {
+				return err
+			}
+			if useRegex {
+				re, err := compileRegex("REPLACE", old, !caseSensitive)
+				if err != nil {
+					return err
+				}
+				return returnResult(&object.String{Value: replaceRegex(str, re, replacement)})
+			}
+			if caseSensitive {
+				return returnResult(&object.String{Value: strings.ReplaceAll(str, old, replacement)})
+			}
+			return returnResult(&object.String{Value: replaceFold(str, old, replacement)})
+		},
+	},
+	// TRIM(文字列, [全角空白も除去するか or 除去する文字])
+	"TRIM": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			return trim("TRIM", args, strings.TrimFunc)
+		},
+	},
+	"TRIMLEFT": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			return trim("TRIMLEFT", args, strings.TrimLeftFunc)
+		},
+	},
+	"TRIMRIGHT": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			return trim("TRIMRIGHT", args, strings.TrimRightFunc)
+		},
+	},
+	// CHGMOJ(文字列, 変換方法)
+	"CHGMOJ": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 2 {
+				return wrongNumberOfArguments("CHGMOJ", len(args), "2")
+			}
+			str, err := stringArgument("CHGMOJ", args, 0)
+			if err != nil {
+				return err
+			}
+			mode, err := integerArgument("CHGMOJ", args, 1, 0)
+			if err != nil {
+				return err
+			}
+			switch mode {
+			case builtinConstantValue(STR_SMALL):
+				str = strings.ToLower(str)
+			case builtinConstantValue(STR_LARGE):
+				str = strings.ToUpper(str)
+			case builtinConstantValue(STR_HIRA):
+				str = toHiragana(str)
+			case builtinConstantValue(STR_KATA):
+				str = toKatakana(str)
+			case builtinConstantValue(STR_HANKAKU):
+				str = toHankaku(str)
+			case builtinConstantValue(STR_ZENKAKU):
+				str = toZenkaku(str)
+			default:
+				return newError("argument 2 to `CHGMOJ` not supported, got %d", mode)
+			}
+			return returnResult(&object.String{Value: str})
+		},
+	},
+	// STRREPEAT(文字列, 回数)
+	"STRREPEAT": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 2 {
+				return wrongNumberOfArguments("STRREPEAT", len(args), "2")
+			}
+			str, err := stringArgument("STRREPEAT", args, 0)
+			if err != nil {
+				return err
+			}
+			count, err := integerArgument("STRREPEAT", args, 1, 0)
+			if err != nil {
+				return err
+			}
+			if count < 0 {
+				return newError("argument 2 to `STRREPEAT` should not be negative, got %d", count)
+			}
+			// NOTE: 長さを掛け算するとあふれる場合があるため割り算で比べる
+			if len(str) > 0 && count > maxRepeatLength/int64(len(str)) {
+				return newError("result of `STRREPEAT` is too long, max %d bytes", maxRepeatLength)
+			}
+			return returnResult(&object.String{Value: strings.Repeat(str, int(count))})
+		},
+	},
+	// LENGTHB(文字列) Shift_JISでのバイト数を返す
+	"LENGTHB": {
+		Fn: func(args ...object.BuiltinFuncArgument) object.Object {
+			if len(args) != 1 {
+				return wrongNumberOfArguments("LENGTHB", len(args), "1")
+			}
+			str, err := stringArgument("LENGTHB", args, 0)
+			if err != nil {
+				return err
+			}
+			var length int64
+			for _, r := range str {
+				// NOTE: ASCIIと半角カナは1バイト、それ以外は2バイトとして数える
+				if r < utf8.RuneSelf || isHalfWidthKana(r) {
+					length += 1
+				} else {
+					length += 2
+				}
+			}
+			return returnResult(&object.Integer{Value: length})
+		},
+	},
+}
+
+const (
+	STR_SMALL   = object.BuiltinConstantType("STR_SMALL")
+	STR_LARGE   = object.BuiltinConstantType("STR_LARGE")
+	STR_HIRA    = object.BuiltinConstantType("STR_HIRA")
+	STR_KATA    = object.BuiltinConstantType("STR_KATA")
+	STR_HANKAKU = object.BuiltinConstantType("STR_HANKAKU")
+	STR_ZENKAKU = object.BuiltinConstantType("STR_ZENKAKU")
+)
+
+var stringBuiltinConstants = map[object.BuiltinConstantType]object.Object{
+	STR_SMALL:   newBuiltinConstant(STR_SMALL, 1),
+	STR_LARGE:   newBuiltinConstant(STR_LARGE, 2),
+	STR_HIRA:    newBuiltinConstant(STR_HIRA, 3),
+	STR_KATA:    newBuiltinConstant(STR_KATA, 4),
+	STR_HANKAKU: newBuiltinConstant(STR_HANKAKU, 5),
+	STR_ZENKAKU: newBuiltinConstant(STR_ZENKAKU, 6),
+}
+
+// 大文字小文字を区別せずに一致する全ての位置(文字単位)を返す
+func indexAllFold(runes, sub []rune) []int {
+	positions := []int{}
+	if len(sub) == 0 {
+		return positions
+	}
+	for i := 0; i+len(sub) <= len(runes); i++ {
+		if equalFoldRunes(runes[i:i+len(sub)], sub) {
+			positions = append(positions, i)
+			i += len(sub) - 1
+		}
+	}
+	return positions
+}
+
+func equalFoldRunes(a, b []rune) bool {
+	for i := range a {
+		if unicode.ToUpper(a[i]) != unicode.ToUpper(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func replaceFold(str, old, replacement string) string {
+	runes := []rune(str)
+	oldRunes := []rune(old)
+	positions := indexAllFold(runes, oldRunes)
+	if len(positions) == 0 {
+		return str
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, pos := range positions {
+		out.WriteString(string(runes[last:pos]))
+		out.WriteString(replacement)
+		last = pos + len(oldRunes)
+	}
+	out.WriteString(string(runes[last:]))
+	return out.String()
+}
+
+func trim(name string, args []object.BuiltinFuncArgument, trimFunc func(string, func(rune) bool) string) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return wrongNumberOfArguments(name, len(args), "1 or 2")
+	}
+	str, err := stringArgument(name, args, 0)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: 第2引数が文字列の場合はその文字を除去する
+	if len(args) == 2 {
+		if cutset, ok := args[1].Value.(*object.String); ok {
+			return returnResult(&object.String{Value: trimFunc(str, func(r rune) bool {
+				return strings.ContainsRune(cutset.Value, r)
+			})})
+		}
+	}
+	withZenkakuSpace, err := boolArgument(name, args, 1, false)
+	if err != nil {
+		return err
+	}
+	return returnResult(&object.String{Value: trimFunc(str, func(r rune) bool {
+		// 空白と制御文字を除去する
+		if r <= ' ' || r == 0x7F {
+			return true
+		}
+		return withZenkakuSpace && r == '　'
+	})})
+}
+
+// STRREPEATで作れる文字列の最大バイト数
+const maxRepeatLength = 1 << 27
+
+const (
+	halfWidthKana = "｡｢｣､･ｦｧｨｩｪｫｬｭｮｯｰｱｲｳｴｵｶｷｸｹｺｻｼｽｾｿﾀﾁﾂﾃﾄﾅﾆﾇﾈﾉﾊﾋﾌﾍﾎﾏﾐﾑﾒﾓﾔﾕﾖﾗﾘﾙﾚﾛﾜﾝﾞﾟ"
+	fullWidthKana = "。「」、・ヲァィゥェォャュョッーアイウエオカキクケコサシスセソタチツテトナニヌネノハヒフヘホマミムメモヤユヨラリルレロワン゛゜"
+	// 濁点を付けられる文字と半濁点を付けられる文字
+	voicedKana     = "カキクケコサシスセソタチツテトハヒフヘホ"
+	semiVoicedKana = "ハヒフヘホ"
+)
+
+var (
+	halfToFullKana = map[rune]rune{}
+	fullToHalfKana = map[rune]string{}
+)
+
+func init() {
+	half := []rune(halfWidthKana)
+	full := []rune(fullWidthKana)
+	for i := range half {
+		halfToFullKana[half[i]] = full[i]
+		fullToHalfKana[full[i]] = string(half[i])
+	}
+	for _, r := range voicedKana {
+		fullToHalfKana[r+1] = fullToHalfKana[r] + "ﾞ"
+	}
+	for _, r := range semiVoicedKana {
+		fullToHalfKana[r+2] = fullToHalfKana[r] + "ﾟ"
+	}
+	fullToHalfKana['ヴ'] = "ｳﾞ"
+}
+
+func isHalfWidthKana(r rune) bool {
+	return 0xFF61 <= r && r <= 0xFF9F
+}
+
+func toHankaku(str string) string {
+	var out strings.Builder
+	for _, r := range str {
+		switch {
+		case r == '　':
+			out.WriteRune(' ')
+		case 0xFF01 <= r && r <= 0xFF5E:
+			out.WriteRune(r - 0xFEE0)
+		default:
+			if half, ok := fullToHalfKana[r]; ok {
+				out.WriteString(half)
+			} else {
+				out.WriteRune(r)
+			}
+		}
+	}
+	return out.String()
+}
+
+func toZenkaku(str string) string {
+	var out strings.Builder
+	runes := []rune(str)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == ' ':
+			out.WriteRune('　')
+		case 0x21 <= r && r <= 0x7E:
+			out.WriteRune(r + 0xFEE0)
+		case isHalfWidthKana(r):
+			full := halfToFullKana[r]
+			// NOTE: 後ろの濁点・半濁点は1文字に合成する
+			if i+1 < len(runes) {
+				next := runes[i+1]
+				if next == 'ﾞ' && full == 'ウ' {
+					full = 'ヴ'
+					i++
+				} else if next == 'ﾞ' && strings.ContainsRune(voicedKana, full) {
+					full += 1
+					i++
+				} else if next == 'ﾟ' && strings.ContainsRune(semiVoicedKana, full) {
+					full += 2
+					i++
+				}
+			}
+			out.WriteRune(full)
+		default:
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
+
+// ひらがなとカタカナの文字コードの差
+const kanaOffset = 'ァ' - 'ぁ'
+
+func toHiragana(str string) string {
+	return strings.Map(func(r rune) rune {
+		if 'ァ' <= r && r <= 'ヶ' {
+			return r - kanaOffset
+		}
+		return r
+	}, str)
+}
+
+func toKatakana(str string) string {
+	return strings.Map(func(r rune) rune {
+		if 'ぁ' <= r && r <= 'ゖ' {
+			return r + kanaOffset
+		}
+		return r
+	}, str)
+}
@@ -0,0 +1,60 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+func TestStringBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"COPY_開始位置から最後までを返す", `COPY("abcdef", 3)`, "cdef"},
+		{"COPY_開始位置から指定した文字数を返す", `COPY("abcdef", 2, 3)`, "bcd"},
+		{"COPY_日本語を文字単位で切り出す", `COPY("あいうえお", 2, 2)`, "いう"},
+		{"COPY_開始位置がマイナスの場合は後ろから数える", `COPY("あいうえお", -2)`, "えお"},
+		{"COPY_開始位置が文字数を超える場合は空文字を返す", `COPY("abc", 4)`, ""},
+		{"COPY_文字数が残りを超える場合は最後までを返す", `COPY("abc", 2, 10)`, "bc"},
+		{"POS_見つかった位置を返す", `POS("cd", "abcdcd")`, 3},
+		{"POS_見つからない場合は0を返す", `POS("x", "abc")`, 0},
+		{"POS_大文字小文字を区別しない", `POS("CD", "abcd")`, 3},
+		{"POS_n番目に見つかった位置を返す", `POS("cd", "abcdcd", 2)`, 5},
+		{"POS_マイナスの場合は後ろから探す", `POS("a", "abcabca", -2)`, 4},
+		{"POS_日本語の位置を文字単位で返す", `POS("う", "あいうえお")`, 3},
+		{"REPLACE_全て置換する", `REPLACE("a-b-c", "-", "+")`, "a+b+c"},
+		{"REPLACE_大文字小文字を区別せず置換する", `REPLACE("Hello HELLO", "hello", "bye")`, "bye bye"},
+		{"REPLACE_第5引数がTRUEの場合は大文字小文字を区別する", `REPLACE("Hello hello", "hello", "bye", FALSE, TRUE)`, "Hello bye"},
+		{"REPLACE_正規表現で第5引数がFALSEの場合は大文字小文字を区別しない", `REPLACE("Hello hello", "h.llo", "bye", TRUE, FALSE)`, "bye bye"},
+		{"REPLACE_正規表現は大文字小文字を区別する", `REPLACE("Hello hello", "h.llo", "bye", TRUE)`, "Hello bye"},
+		{"REPLACE_日本語を置換する", `REPLACE("あいあい", "い", "う")`, "あうあう"},
+		{"TRIM_前後の空白と制御文字を除去する", "TRIM(\"  \tabc \")", "abc"},
+		{"TRIM_全角空白はデフォルトでは除去しない", `TRIM("　abc　")`, "　abc　"},
+		{"TRIM_第2引数がTRUEの場合は全角空白も除去する", `TRIM("　abc　", TRUE)`, "abc"},
+		{"TRIM_第2引数が文字列の場合はその文字を除去する", `TRIM("xxabcyx", "xy")`, "abc"},
+		{"TRIMLEFT_前の空白だけを除去する", `TRIMLEFT("  abc  ")`, "abc  "},
+		{"TRIMRIGHT_後ろの空白だけを除去する", `TRIMRIGHT("  abc  ")`, "  abc"},
+		{"CHGMOJ_小文字にする", `CHGMOJ("AbＣ", STR_SMALL)`, "abｃ"},
+		{"CHGMOJ_大文字にする", `CHGMOJ("aBc", STR_LARGE)`, "ABC"},
+		{"CHGMOJ_ひらがなにする", `CHGMOJ("カタカナとヴ", STR_HIRA)`, "かたかなとゔ"},
+		{"CHGMOJ_カタカナにする", `CHGMOJ("ひらがな", STR_KATA)`, "ヒラガナ"},
+		{"CHGMOJ_半角にする", `CHGMOJ("ＡＢＣ　１２３　ガパポ", STR_HANKAKU)`, "ABC 123 ｶﾞﾊﾟﾎﾟ"},
+		{"CHGMOJ_全角にする", `CHGMOJ("ABC 123 ｶﾞﾊﾟﾎﾟｳﾞ", STR_ZENKAKU)`, "ＡＢＣ　１２３　ガパポヴ"},
+		{"STRREPEAT_文字列を繰り返す", `STRREPEAT("あb", 3)`, "あbあbあb"},
+		{"STRREPEAT_0回の場合は空文字を返す", `STRREPEAT("ab", 0)`, ""},
+		{"LENGTHB_ASCIIは1バイトとして数える", `LENGTHB("abc")`, 3},
+		{"LENGTHB_全角文字は2バイトとして数える", `LENGTHB("aあｱ")`, 4},
+		{"STRREPEAT_長すぎる場合はエラーになる", `STRREPEAT("ab", 9223372036854775807)`, &object.Error{Message: "result of `STRREPEAT` is too long, max 134217728 bytes"}},
+		{"STRREPEAT_上限を1バイトでも超える場合はエラーになる", `STRREPEAT("ab", 67108865)`, &object.Error{Message: "result of `STRREPEAT` is too long, max 134217728 bytes"}},
+		{"STRREPEAT_回数がマイナスの場合はエラーになる", `STRREPEAT("ab", -1)`, &object.Error{Message: "argument 2 to `STRREPEAT` should not be negative, got -1"}},
+		{"COPY_引数が足りない場合はエラーになる", `COPY("ab")`, &object.Error{Message: "wrong number of arguments to `COPY`. got=1, want=2 or 3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testExpectedObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
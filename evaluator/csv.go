@@ -0,0 +1,88 @@
+package evaluator
+
+import (
+	"encoding/csv"
+	"strings"
+)
+
+// ダブルクォートで囲まれた区切り文字や改行、""によるエスケープを扱う
+func parseCSV(text string, sep rune) ([][]string, error) {
+	r := csv.NewReader(strings.NewReader(text))
+	r.Comma = sep
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	return r.ReadAll()
+}
+
+// 区切り文字、ダブルクォート、改行を含む値はダブルクォートで囲む
+func formatCSV(records [][]string, sep rune, newline string) (string, error) {
+	var out strings.Builder
+	w := csv.NewWriter(&out)
+	w.Comma = sep
+	w.UseCRLF = newline == "\r\n"
+	if err := w.WriteAll(records); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func csvSeparator(tab bool) rune {
+	if tab {
+		return '\t'
+	}
+	return ','
+}
+
+// lines[start]から始まるCSVの1レコードを返す。ダブルクォートの中で改行している場合は次の行も含める
+// NOTE: 値の中の改行はCSVPARSEと同じくLFにする。2つ目の戻り値はレコードの行数
+func csvRecord(lines []string, start int) (string, int) {
+	record := lines[start]
+	n := 1
+	for strings.Count(record, `"`)%2 == 1 && start+n < len(lines) {
+		record += "\n" + lines[start+n]
+		n++
+	}
+	return record, n
+}
+
+// 区切り文字で分割する。ダブルクォートの中の区切り文字では分割せず、値はファイルに書かれたまま返す
+func splitRawColumns(record string, tab bool) []string {
+	sep := csvSeparator(tab)
+	columns := []string{}
+	inQuote := false
+	start := 0
+	for i, r := range record {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case r == sep && !inQuote:
+			columns = append(columns, record[start:i])
+			start = i + 1
+		}
+	}
+	return append(columns, record[start:])
+}
+
+// 値を囲むダブルクォートを除き、""を"にする
+func unquoteColumn(column string) string {
+	if len(column) < 2 || !strings.HasPrefix(column, `"`) || !strings.HasSuffix(column, `"`) {
+		return column
+	}
+	return strings.ReplaceAll(column[1:len(column)-1], `""`, `"`)
+}
+
+func splitColumns(record string, tab bool) []string {
+	columns := splitRawColumns(record, tab)
+	for i, c := range columns {
+		columns[i] = unquoteColumn(c)
+	}
+	return columns
+}
+
+func joinColumns(columns []string, tab bool) string {
+	line, err := formatCSV([][]string{columns}, csvSeparator(tab), "\n")
+	if err != nil {
+		return strings.Join(columns, string(csvSeparator(tab)))
+	}
+	return strings.TrimSuffix(line, "\n")
+}
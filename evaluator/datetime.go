@@ -0,0 +1,134 @@
+package evaluator
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+// GETTIMEが返す値(2000/01/01 00:00:00からの秒数)の基準日時
+var getTimeOrigin = time.Date(2000, 1, 1, 0, 0, 0, 0, time.Local)
+
+var dateTimeLayouts = []string{
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"20060102150405",
+	"20060102",
+}
+
+// 日時を表す文字列を解釈する
+func parseDateTime(str string) (time.Time, bool) {
+	s := strings.TrimSpace(str)
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// GETTIMEの値(2000/01/01 00:00:00からの秒数)を日時に変換する
+func timeFromGetTime(seconds float64) time.Time {
+	whole := math.Floor(seconds)
+	return time.Date(2000, 1, 1, 0, 0, int(whole), int((seconds-whole)*float64(time.Second)), time.Local)
+}
+
+// 日時をGETTIMEの値にする
+// NOTE: 夏時間の影響を受けないように、時計の表示のまま秒数を数える
+func getTimeFromTime(t time.Time) int64 {
+	return wallClock(t).Unix() - wallClock(getTimeOrigin).Unix()
+}
+
+var japaneseWeekdays = []string{"日", "月", "火", "水", "木", "金", "土"}
+
+// 書式の記号ごとの変換処理。長い記号から順に照合する
+var dateTimeFormatters = []struct {
+	symbol string
+	format func(t time.Time) string
+}{
+	{"yyyy", func(t time.Time) string { return t.Format("2006") }},
+	{"yy", func(t time.Time) string { return t.Format("06") }},
+	{"mm", func(t time.Time) string { return t.Format("01") }},
+	{"dddd", func(t time.Time) string { return t.Weekday().String() }},
+	{"ddd", func(t time.Time) string { return t.Format("Mon") }},
+	{"dd", func(t time.Time) string { return t.Format("02") }},
+	{"hh", func(t time.Time) string { return t.Format("15") }},
+	{"nn", func(t time.Time) string { return t.Format("04") }},
+	{"ss", func(t time.Time) string { return t.Format("05") }},
+	{"zzz", func(t time.Time) string { return t.Format(".000")[1:] }},
+	{"aaaa", func(t time.Time) string { return japaneseWeekdays[t.Weekday()] + "曜日" }},
+	{"aaa", func(t time.Time) string { return japaneseWeekdays[t.Weekday()] }},
+}
+
+// yyyy/mm/dd hh:nn:ss 形式の書式で日時を文字列にする
+func formatDateTime(t time.Time, format string) string {
+	var out strings.Builder
+	for i := 0; i < len(format); {
+		matched := false
+		for _, f := range dateTimeFormatters {
+			if strings.HasPrefix(strings.ToLower(format[i:]), f.symbol) {
+				out.WriteString(f.format(t))
+				i += len(f.symbol)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			out.WriteByte(format[i])
+			i++
+		}
+	}
+	return out.String()
+}
+
+// 月や年を加算した結果が存在しない日付になる場合は月末にそろえる
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	first = first.AddDate(0, months, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// 夏時間の影響を受けないように、時計の表示のままUTCとして扱う
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+type publicVariable struct {
+	name  string
+	value object.Object
+}
+
+// GETTIMEが設定するG_TIME_*の値
+func timeGlobals(t time.Time) []publicVariable {
+	return []publicVariable{
+		{"G_TIME_YY", &object.Integer{Value: int64(t.Year())}},
+		{"G_TIME_MM", &object.Integer{Value: int64(t.Month())}},
+		{"G_TIME_DD", &object.Integer{Value: int64(t.Day())}},
+		{"G_TIME_HH", &object.Integer{Value: int64(t.Hour())}},
+		{"G_TIME_NN", &object.Integer{Value: int64(t.Minute())}},
+		{"G_TIME_SS", &object.Integer{Value: int64(t.Second())}},
+		{"G_TIME_ZZ", &object.Integer{Value: int64(t.Nanosecond() / int(time.Millisecond))}},
+		{"G_TIME_WW", &object.Integer{Value: int64(t.Weekday())}},
+	}
+}
+
+// G_TIME_*をGETTIMEの呼び出し前でも参照できるように、スクリプトの開始時刻で初期化する
+func initTimeGlobals(env *object.Environment) {
+	if _, ok := env.Get("G_TIME_YY"); ok {
+		return
+	}
+	for _, g := range timeGlobals(env.Now()) {
+		env.SetPublic(g.name, g.value)
+	}
+}
@@ -0,0 +1,59 @@
+package evaluator
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+const (
+	ENCODING_UTF8     = "UTF-8"
+	ENCODING_UTF8_BOM = "UTF-8 BOM"
+	ENCODING_UTF16LE  = "UTF-16LE"
+	ENCODING_UTF16BE  = "UTF-16BE"
+	ENCODING_SJIS     = "Shift_JIS"
+)
+
+var encodings = map[string]encoding.Encoding{
+	ENCODING_UTF8:     unicode.UTF8,
+	ENCODING_UTF8_BOM: unicode.UTF8BOM,
+	ENCODING_UTF16LE:  unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	ENCODING_UTF16BE:  unicode.UTF16(unicode.BigEndian, unicode.UseBOM),
+	ENCODING_SJIS:     japanese.ShiftJIS,
+}
+
+// BOMとUTF-8として正しいかで文字コードを判定する。どちらでもない場合はShift_JISとして扱う
+func detectEncoding(b []byte) string {
+	switch {
+	case bytes.HasPrefix(b, []byte{0xEF, 0xBB, 0xBF}):
+		return ENCODING_UTF8_BOM
+	case bytes.HasPrefix(b, []byte{0xFF, 0xFE}):
+		return ENCODING_UTF16LE
+	case bytes.HasPrefix(b, []byte{0xFE, 0xFF}):
+		return ENCODING_UTF16BE
+	case utf8.Valid(b):
+		return ENCODING_UTF8
+	default:
+		return ENCODING_SJIS
+	}
+}
+
+func decodeText(b []byte) (string, string, error) {
+	enc := detectEncoding(b)
+	decoded, err := encodings[enc].NewDecoder().Bytes(b)
+	if err != nil {
+		return "", enc, err
+	}
+	return string(decoded), enc, nil
+}
+
+func encodeText(text, enc string) ([]byte, error) {
+	e, ok := encodings[enc]
+	if !ok {
+		e = unicode.UTF8
+	}
+	return e.NewEncoder().Bytes([]byte(text))
+}
@@ -2,6 +2,8 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
+	"strings"
 
 	"github.com/sam8helloworld/uwscgo/ast"
 	"github.com/sam8helloworld/uwscgo/object"
@@ -30,24 +32,54 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value}
 	case *ast.DimStatement:
+		if err := checkNotConstant(node.Name.Value, env); err != nil {
+			return err
+		}
 		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
 		env.Set(node.Name.Value, val)
 	case *ast.ConstStatement:
+		if err := checkNotConstant(node.Name.Value, env); err != nil {
+			return err
+		}
 		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
 		env.SetConst(node.Name.Value, val)
+	case *ast.PublicStatement:
+		if err := checkNotConstant(node.Name.Value, env); err != nil {
+			return err
+		}
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		env.SetPublic(node.Name.Value, val)
+	case *ast.OptionStatement:
+		return evalOptionStatement(node, env)
 	case *ast.HashTableStatement:
 		val := Eval(node.Value, env)
-		evalHashTableStatement(node.Name.Value, val, env)
+		if isError(val) {
+			return val
+		}
+		if result := evalHashTableStatement(node, val, env); isError(result) {
+			return result
+		}
 	case *ast.ForToStepStatement:
-		evalForToStepStatement(node, env)
+		return evalForToStepStatement(node, env)
 	case *ast.ForInStatement:
-		evalForInStatement(node, env)
+		return evalForInStatement(node, env)
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
 	case *ast.EmptyArgument:
 		return EMPTY
 	case *ast.Empty:
 		return EMPTY
+	case *ast.Null:
+		return NULL
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, env)
 		if isError(right) {
@@ -63,7 +95,7 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(right) {
 			return right
 		}
-		return evalInfixExpression(node.Operator, left, right)
+		return evalInfixExpression(node.Operator, left, right, env)
 	case *ast.Boolean:
 		return nativeBoolToBooleanObject(node.Value)
 	case *ast.IfStatement:
@@ -75,9 +107,15 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.AssignmentExpression:
 		left := node.Left
 		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
 		return evalAssignExpression(left, val, env)
 	case *ast.ResultStatement:
 		val := Eval(node.ResultValue, env)
+		if isError(val) {
+			return val
+		}
 		return &object.ResultValue{
 			Value: val,
 		}
@@ -105,7 +143,7 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 
 		switch fn := function.(type) {
 		case *object.Function:
-			return applyFunction(fn, args)
+			return applyFunction(fn, args, env, node.Token.Line)
 		case *object.BuiltinFunction:
 			argss := []object.BuiltinFuncArgument{}
 			for i, arg := range args {
@@ -131,7 +169,12 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			if !ok {
 				return newError("array has wrong size: %s", node.String())
 			}
-			return &object.Array{Elements: make([]object.Object, sizeObj.Value+1)}
+			// NOTE: 初期値のない要素はEMPTYとする
+			elements := make([]object.Object, sizeObj.Value+1)
+			for i := range elements {
+				elements[i] = EMPTY
+			}
+			return &object.Array{Elements: elements}
 		}
 		// 初期値も存在する
 		if size != nil {
@@ -162,24 +205,80 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			opt = Eval(node.Option, env)
 		}
 		return evalIndexExpression(left, index, opt)
+	case *ast.DotExpression:
+		left := Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		return evalDotExpression(left, node.Member.Value)
 	}
 	return nil
 }
 
-func applyFunction(fn *object.Function, args []object.Object) object.Object {
+// Goのpanicをスクリプトのエラーとして返す
+func EvalSafely(node ast.Node, env *object.Environment) (result object.Object) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = newError("internal error: %v", r)
+		}
+	}()
+	return Eval(node, env)
+}
+
+func applyFunction(fn *object.Function, args []object.Object, env *object.Environment, line int) object.Object {
+	callStack := env.CallStack()
+	if !callStack.Push(object.CallFrame{Name: fn.Name, Line: line}) {
+		err := newError("maximum call depth exceeded: %d", callStack.MaxDepth)
+		err.Line = line
+		err.Stack = callStack.Frames()
+		return err
+	}
+	defer callStack.Pop()
+
+	if len(args) != len(fn.Parameters) {
+		return newError("wrong number of arguments to `%s`. got=%d, want=%d", fn.Name, len(args), len(fn.Parameters))
+	}
+
 	extendedEnv := extendFunctionEnv(fn, args)
 	evaluated := Eval(fn.Body, extendedEnv)
+	if err, ok := evaluated.(*object.Error); ok {
+		// 最も内側の関数で呼び出し履歴を記録する
+		if err.Stack == nil {
+			err.Stack = callStack.Frames()
+		}
+		return err
+	}
+	// NOTE: PROCEDUREは戻り値を持たない
+	if fn.IsProc {
+		return NULL
+	}
 	return unwrapReturnValue(evaluated)
 }
 
 func applyBuiltinFunction(fn *object.BuiltinFunction, args []object.BuiltinFuncArgument, env *object.Environment) object.Object {
-	result := fn.Fn(args...)
+	var result object.Object
+	if fn.EnvFn != nil {
+		result = fn.EnvFn(env, args...)
+	} else {
+		result = fn.Fn(args...)
+	}
 	switch r := result.(type) {
 	case *object.BuiltinFuncReturnResult:
 		return r.Value
 	case *object.BuiltinFuncReturnReference:
-		evalAssignExpression(r.Expression, r.Value, env)
+		if assigned := evalAssignExpression(r.Expression, r.Value, env); isError(assigned) {
+			return assigned
+		}
 		return r.Result
+	case *object.BuiltinFuncReturnReferences:
+		for _, ref := range r.References {
+			if assigned := evalAssignExpression(ref.Expression, ref.Value, env); isError(assigned) {
+				return assigned
+			}
+		}
+		return r.Result
+	case *object.Error:
+		return r
 	default:
 		return newError("not a function: %s", fn.Type())
 	}
@@ -211,13 +310,27 @@ func unwrapReturnValue(obj object.Object) object.Object {
 func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	var result object.Object
 
+	initTimeGlobals(env)
+	if err := hoistDeclarations(program, env); err != nil {
+		err.Trace = formatStackTrace(err)
+		return err
+	}
+
 	for _, statement := range program.Statements {
+		if isHoisted(statement) {
+			continue
+		}
+		if err := checkCancelled(env); err != nil {
+			return err
+		}
 		result = Eval(statement, env)
 
 		switch result := result.(type) {
 		case *object.ResultValue:
 			return result.Value
 		case *object.Error:
+			setErrorLine(result, statement)
+			result.Trace = formatStackTrace(result)
 			return result
 		}
 	}
@@ -225,20 +338,132 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	return result
 }
 
+// UWSCと同様に、実行前に関数定義とPUBLIC・CONSTの宣言を登録する
+func hoistDeclarations(program *ast.Program, env *object.Environment) *object.Error {
+	for _, statement := range program.Statements {
+		if stmt, ok := statement.(*ast.FunctionStatement); ok {
+			Eval(stmt, env)
+		}
+	}
+	// NOTE: 宣言の値から関数を呼び出せるように関数の後に評価する
+	for _, statement := range program.Statements {
+		switch statement.(type) {
+		case *ast.ConstStatement, *ast.PublicStatement:
+			if err, ok := Eval(statement, env).(*object.Error); ok {
+				setErrorLine(err, statement)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isHoisted(statement ast.Statement) bool {
+	switch statement.(type) {
+	case *ast.FunctionStatement, *ast.ConstStatement, *ast.PublicStatement:
+		return true
+	default:
+		return false
+	}
+}
+
 func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
 	var result object.Object
 
 	for _, stmt := range block.Statements {
+		if err := checkCancelled(env); err != nil {
+			return err
+		}
 		result = Eval(stmt, env)
 
-		if result != nil && result.Type() == object.RESULT_VALUE_OBJ {
-			return result
+		if result != nil {
+			rt := result.Type()
+			if rt == object.RESULT_VALUE_OBJ {
+				return result
+			}
+			if rt == object.ERROR_OBJ {
+				setErrorLine(result.(*object.Error), stmt)
+				return result
+			}
 		}
 	}
 
 	return result
 }
 
+// ホストからコンテキストがキャンセルされた場合はスクリプトを中断する
+func checkCancelled(env *object.Environment) *object.Error {
+	if err := env.Context().Err(); err != nil {
+		return interruptedError(err)
+	}
+	return nil
+}
+
+func interruptedError(err error) *object.Error {
+	return newError("script interrupted: %s", err)
+}
+
+// エラーが発生した文の行番号を記録する(内側の文が優先)
+func setErrorLine(err *object.Error, stmt ast.Statement) {
+	if err.Line != 0 || stmt == nil {
+		return
+	}
+	err.Line = statementLine(stmt)
+}
+
+func statementLine(stmt ast.Statement) int {
+	switch stmt := stmt.(type) {
+	case *ast.DimStatement:
+		return stmt.Token.Line
+	case *ast.ConstStatement:
+		return stmt.Token.Line
+	case *ast.PublicStatement:
+		return stmt.Token.Line
+	case *ast.OptionStatement:
+		return stmt.Token.Line
+	case *ast.HashTableStatement:
+		return stmt.Token.Line
+	case *ast.ExpressionStatement:
+		return stmt.Token.Line
+	case *ast.IfStatement:
+		return stmt.Token.Line
+	case *ast.IfbStatement:
+		return stmt.Token.Line
+	case *ast.FunctionStatement:
+		return stmt.Token.Line
+	case *ast.ResultStatement:
+		return stmt.Token.Line
+	case *ast.ForToStepStatement:
+		return stmt.Token.Line
+	case *ast.ForInStatement:
+		return stmt.Token.Line
+	default:
+		return 0
+	}
+}
+
+// スタックトレースの表示行数の上限(超えた分は中略する)
+const maxStackTraceLines = 20
+
+func formatStackTrace(err *object.Error) string {
+	lines := []string{}
+	line := err.Line
+	for _, frame := range err.Stack {
+		lines = append(lines, fmt.Sprintf("\tat %s (line %d)", frame.Name, line))
+		line = frame.Line
+	}
+	lines = append(lines, fmt.Sprintf("\tat <main> (line %d)", line))
+
+	if len(lines) > maxStackTraceLines {
+		half := maxStackTraceLines / 2
+		omitted := len(lines) - maxStackTraceLines
+		trimmed := append([]string{}, lines[:half]...)
+		trimmed = append(trimmed, fmt.Sprintf("\t... %d more frames ...", omitted))
+		lines = append(trimmed, lines[len(lines)-half:]...)
+	}
+	return strings.Join(lines, "\n")
+}
+
 func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object {
 	if val, ok := env.Get(node.Value); ok {
 		return val
@@ -259,49 +484,153 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 }
 
 func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
-	if right.Type() != object.INTEGER_OBJ {
+	switch right := right.(type) {
+	case *object.Integer:
+		// NOTE: 最小値の符号反転は桁あふれするため実数にする
+		if right.Value == math.MinInt64 {
+			return &object.Float{Value: -float64(right.Value)}
+		}
+		return &object.Integer{Value: -right.Value}
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	default:
 		return newError("unknown operator: -%s", right.Type())
 	}
+}
 
-	value := right.(*object.Integer).Value
-	return &object.Integer{Value: -value}
+func isNumber(obj object.Object) bool {
+	t := obj.Type()
+	return t == object.INTEGER_OBJ || t == object.FLOAT_OBJ
 }
 
-func evalInfixExpression(operator string, left, right object.Object) object.Object {
+func toFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value)
+	case *object.Float:
+		return obj.Value
+	default:
+		return 0
+	}
+}
+
+func evalInfixExpression(operator string, left, right object.Object, env *object.Environment) object.Object {
+	// NOTE: 組み込み定数は値として計算する(HASH_CASECARE OR HASH_SORTなど)
+	if cons, ok := left.(*object.BuiltinConstant); ok {
+		left = cons.Value
+	}
+	if cons, ok := right.(*object.BuiltinConstant); ok {
+		right = cons.Value
+	}
+	if operator == "AND" || operator == "OR" || operator == "XOR" {
+		return evalLogicalInfixExpression(operator, left, right)
+	}
+	if left == NULL || right == NULL {
+		return evalNullInfixExpression(operator, left, right)
+	}
+	if left == EMPTY || right == EMPTY {
+		return evalEmptyInfixExpression(operator, left, right, env)
+	}
+
 	switch {
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right)
+	case isNumber(left) && isNumber(right):
+		return evalFloatInfixExpression(operator, left, right)
+	case operator == "+" && isConcatenatable(left, right):
+		// NOTE: どちらかが文字列の場合は文字列として連結する
+		return &object.String{Value: toStringValue(left) + toStringValue(right)}
+	case isNumber(left) && right.Type() == object.STRING_OBJ,
+		left.Type() == object.STRING_OBJ && isNumber(right):
+		return evalMixedInfixExpression(operator, left, right, env)
 	case left.Type() != right.Type():
 		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
-		return evalStringInfixExpression(operator, left, right)
+		return evalStringInfixExpression(operator, left, right, env)
+	case left.Type() == object.BOOLEAN_OBJ && (operator == "=" || operator == "<>"):
+		return nativeBoolToBooleanObject((left == right) == (operator == "="))
 	default:
 		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
+func isConcatenatable(left, right object.Object) bool {
+	if left.Type() != object.STRING_OBJ && right.Type() != object.STRING_OBJ {
+		return false
+	}
+	return (left.Type() == object.STRING_OBJ || isNumber(left)) &&
+		(right.Type() == object.STRING_OBJ || isNumber(right))
+}
+
+// 数値と文字列の演算は文字列を数値に変換して計算する
+// 比較で数値に変換できない場合は文字列として比較する
+func evalMixedInfixExpression(operator string, left, right object.Object, env *object.Environment) object.Object {
+	leftNum, leftOk := toNumber(left)
+	rightNum, rightOk := toNumber(right)
+	if leftOk && rightOk {
+		return evalInfixExpression(operator, leftNum, rightNum, env)
+	}
+	if isComparisonOperator(operator) {
+		return evalStringInfixExpression(
+			operator,
+			&object.String{Value: toStringValue(left)},
+			&object.String{Value: toStringValue(right)},
+			env,
+		)
+	}
+	if left.Type() == right.Type() {
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+	return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+}
+
 func evalIntegerInfixExpression(operator string, left, right object.Object) object.Object {
 	leftVal := left.(*object.Integer).Value
 	rightVal := right.(*object.Integer).Value
 
+	// NOTE: UWSCの数値は倍精度実数なので、桁あふれする場合は実数で計算する
 	switch operator {
 	case "+":
+		sum := leftVal + rightVal
+		if (leftVal >= 0) == (rightVal >= 0) && (sum >= 0) != (leftVal >= 0) {
+			return evalFloatInfixExpression(operator, left, right)
+		}
 		return &object.Integer{
-			Value: leftVal + rightVal,
+			Value: sum,
 		}
 	case "-":
+		diff := leftVal - rightVal
+		if (leftVal >= 0) != (rightVal >= 0) && (diff >= 0) != (leftVal >= 0) {
+			return evalFloatInfixExpression(operator, left, right)
+		}
 		return &object.Integer{
-			Value: leftVal - rightVal,
+			Value: diff,
 		}
 	case "*":
+		product := leftVal * rightVal
+		if leftVal != 0 && (product/leftVal != rightVal || (leftVal == -1 && rightVal == math.MinInt64)) {
+			return evalFloatInfixExpression(operator, left, right)
+		}
 		return &object.Integer{
-			Value: leftVal * rightVal,
+			Value: product,
 		}
 	case "/":
+		if rightVal == 0 {
+			return newError("division by zero: %d / %d", leftVal, rightVal)
+		}
+		if leftVal == math.MinInt64 && rightVal == -1 {
+			return evalFloatInfixExpression(operator, left, right)
+		}
 		return &object.Integer{
 			Value: leftVal / rightVal,
 		}
 	case "MOD":
+		if rightVal == 0 {
+			return newError("division by zero: %d MOD %d", leftVal, rightVal)
+		}
+		if rightVal == -1 {
+			return &object.Integer{Value: 0}
+		}
 		return &object.Integer{
 			Value: leftVal % rightVal,
 		}
@@ -322,6 +651,134 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 	}
 }
 
+// NULLはNULLとだけ等しい
+func evalNullInfixExpression(operator string, left, right object.Object) object.Object {
+	switch operator {
+	case "=":
+		return nativeBoolToBooleanObject(left == right)
+	case "<>":
+		return nativeBoolToBooleanObject(left != right)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// EMPTYは相手の型に合わせて空文字、0、FALSEとして扱う
+func evalEmptyInfixExpression(operator string, left, right object.Object, env *object.Environment) object.Object {
+	if left == EMPTY {
+		left = emptyValueFor(right)
+	}
+	if right == EMPTY {
+		right = emptyValueFor(left)
+	}
+	return evalInfixExpression(operator, left, right, env)
+}
+
+func emptyValueFor(other object.Object) object.Object {
+	switch other.Type() {
+	case object.STRING_OBJ:
+		return &object.String{Value: ""}
+	case object.BOOLEAN_OBJ:
+		return FALSE
+	default:
+		return &object.Integer{Value: 0}
+	}
+}
+
+func isComparisonOperator(operator string) bool {
+	switch operator {
+	case "=", "<>", "<", "<=", ">", ">=":
+		return true
+	default:
+		return false
+	}
+}
+
+// 真偽値同士は論理演算、整数を含む場合はビット演算をする
+func evalLogicalInfixExpression(operator string, left, right object.Object) object.Object {
+	leftBool, leftIsBool := left.(*object.Boolean)
+	rightBool, rightIsBool := right.(*object.Boolean)
+	if leftIsBool && rightIsBool {
+		switch operator {
+		case "AND":
+			return nativeBoolToBooleanObject(leftBool.Value && rightBool.Value)
+		case "OR":
+			return nativeBoolToBooleanObject(leftBool.Value || rightBool.Value)
+		default:
+			return nativeBoolToBooleanObject(leftBool.Value != rightBool.Value)
+		}
+	}
+
+	leftVal, ok := toBitwiseOperand(left)
+	if !ok {
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+	rightVal, ok := toBitwiseOperand(right)
+	if !ok {
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+	switch operator {
+	case "AND":
+		return &object.Integer{Value: leftVal & rightVal}
+	case "OR":
+		return &object.Integer{Value: leftVal | rightVal}
+	default:
+		return &object.Integer{Value: leftVal ^ rightVal}
+	}
+}
+
+func toBitwiseOperand(obj object.Object) (int64, bool) {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return obj.Value, true
+	case *object.Boolean:
+		if obj.Value {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+func evalFloatInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := toFloat(left)
+	rightVal := toFloat(right)
+
+	switch operator {
+	case "+":
+		return &object.Float{Value: leftVal + rightVal}
+	case "-":
+		return &object.Float{Value: leftVal - rightVal}
+	case "*":
+		return &object.Float{Value: leftVal * rightVal}
+	case "/":
+		if rightVal == 0 {
+			return newError("division by zero: %s / %s", left.Inspect(), right.Inspect())
+		}
+		return &object.Float{Value: leftVal / rightVal}
+	case "MOD":
+		if rightVal == 0 {
+			return newError("division by zero: %s MOD %s", left.Inspect(), right.Inspect())
+		}
+		return &object.Float{Value: math.Mod(leftVal, rightVal)}
+	case "=":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "<>":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
 func nativeBoolToBooleanObject(input bool) *object.Boolean {
 	if input {
 		return TRUE
@@ -363,11 +820,11 @@ func isTruthy(obj object.Object) bool {
 	if obj == FALSE {
 		return false
 	}
-	if obj.Type() == object.INTEGER_OBJ {
-		v := obj.(*object.Integer).Value
-		if v == 0 {
-			return false
-		}
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return obj.Value != 0
+	case *object.Float:
+		return obj.Value != 0
 	}
 	return true
 }
@@ -375,57 +832,132 @@ func isTruthy(obj object.Object) bool {
 func evalAssignExpression(left ast.Expression, val object.Object, env *object.Environment) object.Object {
 	switch l := left.(type) {
 	case *ast.Identifier:
-		ident, ok := env.Get(l.Value)
+		binded, ok := env.BindedObject(l.Value)
 		if !ok {
-			return newError("identifier is not defined: %s", l.String())
+			if env.Option(OPTION_EXPLICIT) {
+				return newError("identifier is not defined: %s", l.String())
+			}
+			// NOTE: OPTION EXPLICITがない場合は代入で変数が宣言される
+			return env.Set(l.Value, val)
 		}
-		if _, ok := ident.(*object.HashTable); ok {
+		if binded.Type == object.CONST {
+			return newError("cannot assign to constant: %s", binded.Key)
+		}
+		if ht, ok := binded.Object.(*object.HashTable); ok {
 			if cons, ok := val.(*object.BuiltinConstant); ok {
 				if cons.T == HASH_REMOVEALL {
-					ht := &object.HashTable{
-						Pairs: map[object.HashKey]object.HashPair{},
-					}
-					env.Set(l.Value, ht)
-					return ht
+					cleared := object.NewHashTable(ht.IsSort, ht.IsCasecare)
+					binded.Object = cleared
+					return cleared
 				}
 			}
 		}
-		env.Set(l.Value, val)
+		// NOTE: 宣言された環境の束縛を更新する(PUBLIC変数を関数内から書き換えられるように)
+		binded.Object = val
 	case *ast.IndexExpression:
-		ident, ok := l.Left.(*ast.Identifier)
-		if !ok {
-			return newError("index expression left should be identifier: %s", l.Left.String())
+		target, err := evalAssignTarget(l.Left, env)
+		if err != nil {
+			return err
 		}
-		aoh, ok := env.Get(ident.Value)
-		if !ok {
-			return newError("identifier is not defined: %s", ident.String())
+		index := Eval(l.Index, env)
+		if isError(index) {
+			return index
 		}
-		switch aoh := aoh.(type) {
+		switch aoh := target.(type) {
 		case *object.Array:
-			index, ok := l.Index.(*ast.IntegerLiteral)
+			i, ok := index.(*object.Integer)
 			if !ok {
 				return newError("index sholud be integer: %s", l.Index.String())
 			}
-			aoh.Elements[int(index.Value)] = val
-			env.Set(ident.Value, aoh)
+			if i.Value < 0 || i.Value >= int64(len(aoh.Elements)) {
+				return newError("index out of range: %s[%d]", l.Left.String(), i.Value)
+			}
+			aoh.Elements[int(i.Value)] = val
 		case *object.HashTable:
-			index := Eval(l.Index, env)
 			key, ok := index.(object.Hashable)
 			if !ok {
 				return newError("unusable as hash key: %s", index.Type())
 			}
-			aoh.Pairs[key.HashKey()] = object.HashPair{
-				Key:   index,
-				Value: val,
-			}
+			aoh.Set(key, val)
 			return aoh
 		default:
 			return newError("index should be with array or hash: %s", l.Left.String())
 		}
+	case *ast.DotExpression:
+		target, err := evalAssignTarget(l.Left, env)
+		if err != nil {
+			return err
+		}
+		hash, ok := target.(*object.HashTable)
+		if !ok {
+			return newError("dot operator not supported: %s", target.Type())
+		}
+		hash.Set(&object.String{Value: l.Member.Value}, val)
+		return hash
 	}
 	return val
 }
 
+// 添字で代入する対象の配列や連想配列を返す(array[0]["key"] = 1 のような入れ子も辿る)
+func evalAssignTarget(left ast.Expression, env *object.Environment) (object.Object, *object.Error) {
+	switch left := left.(type) {
+	case *ast.Identifier:
+		binded, ok := env.BindedObject(left.Value)
+		if !ok {
+			return nil, newError("identifier is not defined: %s", left.String())
+		}
+		if binded.Type == object.CONST {
+			return nil, newError("cannot assign to constant: %s", binded.Key)
+		}
+		return binded.Object, nil
+	case *ast.IndexExpression:
+		// NOTE: 定数の要素を書き換えないように根元の識別子を確認する
+		if _, err := evalAssignTarget(left.Left, env); err != nil {
+			return nil, err
+		}
+		target := Eval(left, env)
+		if err, ok := target.(*object.Error); ok {
+			return nil, err
+		}
+		return target, nil
+	case *ast.DotExpression:
+		if _, err := evalAssignTarget(left.Left, env); err != nil {
+			return nil, err
+		}
+		target := Eval(left, env)
+		if err, ok := target.(*object.Error); ok {
+			return nil, err
+		}
+		return target, nil
+	default:
+		return nil, newError("index expression left should be identifier: %s", left.String())
+	}
+}
+
+// 定数の再宣言はできない
+func checkNotConstant(name string, env *object.Environment) *object.Error {
+	if binded, ok := env.BindedObject(name); ok && binded.Type == object.CONST {
+		return newError("cannot assign to constant: %s", binded.Key)
+	}
+	return nil
+}
+
+const (
+	OPTION_EXPLICIT = "EXPLICIT" // 未宣言の変数への代入を禁止する
+	OPTION_SAMESTR  = "SAMESTR"  // 文字列の比較で大文字小文字を区別する
+)
+
+func evalOptionStatement(node *ast.OptionStatement, env *object.Environment) object.Object {
+	name := strings.ToUpper(node.Name.Value)
+	switch name {
+	case OPTION_EXPLICIT, OPTION_SAMESTR:
+		env.SetOption(name, true)
+		return nil
+	default:
+		return newError("unknown option: %s", node.Name.Value)
+	}
+}
+
 func newError(format string, a ...interface{}) *object.Error {
 	return &object.Error{Message: fmt.Sprintf(format, a...)}
 }
@@ -444,15 +976,46 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	return result
 }
 
-func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
-	if operator != "+" {
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+func evalStringInfixExpression(operator string, left, right object.Object, env *object.Environment) object.Object {
+	switch operator {
+	case "-", "*", "/", "MOD":
+		// NOTE: 文字列同士でも連結以外の算術演算は数値に変換して計算する
+		return evalMixedInfixExpression(operator, left, right, env)
 	}
 	leftVal := left.(*object.String).Value
 	rightVal := right.(*object.String).Value
+	if isComparisonOperator(operator) {
+		return evalStringComparison(operator, leftVal, rightVal, env.Option(OPTION_SAMESTR))
+	}
+	if operator != "+" {
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
 	return &object.String{Value: leftVal + rightVal}
 }
 
+// OPTION SAMESTRがない場合は大文字小文字を区別せずに比較する
+func evalStringComparison(operator, left, right string, caseSensitive bool) object.Object {
+	if !caseSensitive {
+		left = strings.ToLower(left)
+		right = strings.ToLower(right)
+	}
+	cmp := strings.Compare(left, right)
+	switch operator {
+	case "=":
+		return nativeBoolToBooleanObject(cmp == 0)
+	case "<>":
+		return nativeBoolToBooleanObject(cmp != 0)
+	case "<":
+		return nativeBoolToBooleanObject(cmp < 0)
+	case "<=":
+		return nativeBoolToBooleanObject(cmp <= 0)
+	case ">":
+		return nativeBoolToBooleanObject(cmp > 0)
+	default:
+		return nativeBoolToBooleanObject(cmp >= 0)
+	}
+}
+
 func evalIndexExpression(left, index, opt object.Object) object.Object {
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
@@ -489,20 +1052,21 @@ func evalHashTableIndexExpression(hash, index, opt object.Object) object.Object
 			return newError("option should be builtin constant: %s", opt.Value.Inspect())
 		}
 		if opt.T == HASH_EXISTS {
-			_, ok := hashObject.Pairs[key.HashKey()]
-			return &object.Boolean{
-				Value: ok,
-			}
+			_, ok := hashObject.Get(key)
+			return nativeBoolToBooleanObject(ok)
 		}
 		if opt.T == HASH_REMOVE {
-			delete(hashObject.Pairs, key.HashKey())
+			return nativeBoolToBooleanObject(hashObject.Remove(key))
 		}
 		if opt.T == HASH_KEY {
 			i, ok := key.(*object.Integer)
 			if !ok {
 				return newError("unusable as hash key: %s", key.HashKey().Type)
 			}
-			pair := hashObject.GetPairByIndex(int(i.Value))
+			pair, ok := hashObject.GetPairByIndex(int(i.Value))
+			if !ok {
+				return newError("hash index out of range: %d", i.Value)
+			}
 
 			return pair.Key
 		}
@@ -511,40 +1075,72 @@ func evalHashTableIndexExpression(hash, index, opt object.Object) object.Object
 			if !ok {
 				return newError("unusable as hash key: %s", key.HashKey().Type)
 			}
-			pair := hashObject.GetPairByIndex(int(i.Value))
+			pair, ok := hashObject.GetPairByIndex(int(i.Value))
+			if !ok {
+				return newError("hash index out of range: %d", i.Value)
+			}
 
 			return pair.Value
 		}
 	}
 
-	pair, ok := hashObject.Pairs[key.HashKey()]
+	val, ok := hashObject.Get(key)
 	if !ok {
 		return NULL
 	}
 
-	return pair.Value
+	return val
 }
 
-func evalHashTableStatement(name string, value object.Object, env *object.Environment) object.Object {
-	var casecare bool = false
-	var sort bool = false
+// hash.key は hash["key"] と同じ値を返す
+func evalDotExpression(left object.Object, member string) object.Object {
+	hashObject, ok := left.(*object.HashTable)
+	if !ok {
+		return newError("dot operator not supported: %s", left.Type())
+	}
+	val, ok := hashObject.Get(&object.String{Value: member})
+	if !ok {
+		return NULL
+	}
+	return val
+}
+
+func evalHashTableStatement(node *ast.HashTableStatement, value object.Object, env *object.Environment) object.Object {
+	var flags int64
 	switch val := value.(type) {
 	case *object.BuiltinConstant:
-		if val.T == HASH_CASECARE {
-			casecare = true
-		}
-		if val.T == HASH_SORT {
-			sort = true
+		i, ok := val.Value.(*object.Integer)
+		if !ok {
+			return newError("unknown hash declare: %s", val.Inspect())
 		}
+		flags = i.Value
+	case *object.Integer:
+		// NOTE: HASH_CASECARE OR HASH_SORT のように組み合わせた場合
+		flags = val.Value
 	case *object.Empty:
 	default:
 		return newError("unknown hash declare: %s", val.Inspect())
 	}
-	return env.Set(name, &object.HashTable{
-		Pairs:      map[object.HashKey]object.HashPair{},
-		IsSort:     sort,
-		IsCasecare: casecare,
-	})
+	sort := flags&builtinConstantValue(HASH_SORT) != 0
+	casecare := flags&builtinConstantValue(HASH_CASECARE) != 0
+	hash := object.NewHashTable(sort, casecare)
+	// NOTE: HASHTBL hash = "a", 1, "b", 2 のようにキーと値の組で初期化する場合
+	for i, keyNode := range node.Keys {
+		key := Eval(keyNode, env)
+		if isError(key) {
+			return key
+		}
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", key.Type())
+		}
+		value := Eval(node.Values[i], env)
+		if isError(value) {
+			return value
+		}
+		hash.Set(hashKey, value)
+	}
+	return env.Set(node.Name.Value, hash)
 }
 
 func evalForToStepStatement(forStmt *ast.ForToStepStatement, env *object.Environment) object.Object {
@@ -569,6 +1165,9 @@ Loop:
 			Value: i,
 		}
 		env.Set(forStmt.LoopVar.Value, index)
+		if err := checkCancelled(env); err != nil {
+			return err
+		}
 		for _, stmt := range forStmt.Block.Statements {
 			if _, ok := stmt.(*ast.ContinueStatement); ok {
 				continue Loop
@@ -576,7 +1175,10 @@ Loop:
 			if _, ok := stmt.(*ast.BreakStatement); ok {
 				break Loop
 			}
-			Eval(stmt, env)
+			if result := Eval(stmt, env); isError(result) {
+				setErrorLine(result.(*object.Error), stmt)
+				return result
+			}
 		}
 	}
 	return nil
@@ -596,6 +1198,9 @@ func evalForInStatement(forStmt *ast.ForInStatement, env *object.Environment) ob
 Loop:
 	for _, element := range collectObject.Elements {
 		env.Set(forStmt.LoopVar.Value, element)
+		if err := checkCancelled(env); err != nil {
+			return err
+		}
 
 		for _, stmt := range forStmt.Block.Statements {
 			if _, ok := stmt.(*ast.ContinueStatement); ok {
@@ -604,7 +1209,10 @@ Loop:
 			if _, ok := stmt.(*ast.BreakStatement); ok {
 				break Loop
 			}
-			Eval(stmt, env)
+			if result := Eval(stmt, env); isError(result) {
+				setErrorLine(result.(*object.Error), stmt)
+				return result
+			}
 		}
 	}
 	return nil
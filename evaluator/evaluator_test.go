@@ -1,8 +1,10 @@
 package evaluator_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/sam8helloworld/uwscgo/ast"
 	"github.com/sam8helloworld/uwscgo/evaluator"
 	"github.com/sam8helloworld/uwscgo/lexer"
 	"github.com/sam8helloworld/uwscgo/object"
@@ -61,10 +63,14 @@ func TestEvalIntegerExpression(t *testing.T) {
 }
 
 func testEval(input string) object.Object {
+	return testEvalEnv(object.NewEnvironment(), input)
+}
+
+// 乱数の種などを設定した環境で評価する
+func testEvalEnv(env *object.Environment, input string) object.Object {
 	l := lexer.NewLexer(input)
 	p := parser.NewParser(l)
 	program := p.ParseProgram()
-	env := object.NewEnvironment()
 
 	return evaluator.Eval(program, env)
 }
@@ -578,6 +584,84 @@ CALCARRAY(array, CALC_ADD, ,2)`,
 CALCARRAY(array, CALC_ADD, 2, 3)`,
 			7,
 		},
+		{
+			"CALCARRAY_第2引数にCALC_AVRを指定して要素の平均値を求める",
+			`DIM array[] = 1, 2, 3, 4
+CALCARRAY(array, CALC_AVR)`,
+			2.5,
+		},
+		{
+			"CALCARRAY_平均値が整数の場合は整数を返す",
+			`DIM array[] = 1, 2, 3
+CALCARRAY(array, CALC_AVR)`,
+			2,
+		},
+		{
+			"CALCARRAY_開始位置を指定してCALC_MINで最小値を求める",
+			`DIM array[] = 1, 5, 3, 4
+CALCARRAY(array, CALC_MIN, 1)`,
+			3,
+		},
+		{
+			"CALCARRAY_開始位置を指定してCALC_MAXで最大値を求める",
+			`DIM array[] = 9, 5, 3, 4
+CALCARRAY(array, CALC_MAX, 1, 3)`,
+			5,
+		},
+		{
+			"CALCARRAY_計算終了位置にEMPTYを指定すると最後まで計算する",
+			`DIM array[] = 1, 2, 3
+CALCARRAY(array, CALC_ADD, 1, EMPTY)`,
+			5,
+		},
+		{
+			"CALCARRAY_EMPTYの要素は計算から除く",
+			`DIM array[3]
+array[0] = 2
+array[2] = 4
+CALCARRAY(array, CALC_AVR)`,
+			3,
+		},
+		{
+			"CALCARRAY_数値に変換できる文字列は数値として計算する",
+			`DIM array[] = 1, "2", "a"
+CALCARRAY(array, CALC_ADD)`,
+			3,
+		},
+		{
+			"CALCARRAY_実数を含む配列を計算する",
+			`DIM array[] = 1, VAL("0.5")
+CALCARRAY(array, CALC_ADD)`,
+			1.5,
+		},
+		{
+			"CALCARRAY_計算終了位置が配列の範囲外の場合はエラーになる",
+			`DIM array[] = 1, 2, 3
+CALCARRAY(array, CALC_ADD, 0, 3)`,
+			"index out of range in `CALCARRAY`. to=3, length=3",
+		},
+		{
+			"CALCARRAY_計算開始位置が配列の範囲外の場合はエラーになる",
+			`DIM array[] = 1, 2, 3
+CALCARRAY(array, CALC_ADD, -1)`,
+			"index out of range in `CALCARRAY`. from=-1, length=3",
+		},
+		{
+			"CALCARRAY_空の配列のCALC_ADDは0を返す",
+			`CALCARRAY(MATCH("abc", "\d"), CALC_ADD)`,
+			0,
+		},
+		{
+			"CALCARRAY_空の配列のCALC_AVRは0を返す",
+			`DIM array[] = 1, 2
+CALCARRAY(SLICE(array, 5), CALC_AVR)`,
+			0,
+		},
+		{
+			"CALCARRAY_空の配列のCALC_MINはEMPTYを返す",
+			`VARTYPE(CALCARRAY(MATCH("abc", "\d"), CALC_MIN))`,
+			0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -587,6 +671,8 @@ CALCARRAY(array, CALC_ADD, 2, 3)`,
 			switch expected := tt.expected.(type) {
 			case int:
 				testIntegerObject(t, evaluated, int64(expected))
+			case float64:
+				testFloatObject(t, evaluated, expected)
 			case string:
 				errObj, ok := evaluated.(*object.Error)
 				if !ok {
@@ -901,3 +987,803 @@ sum
 		})
 	}
 }
+
+func TestCallDepthLimit(t *testing.T) {
+	input := `FUNCTION fn(n)
+	RESULT = fn(n + 1)
+FEND
+fn(0)`
+
+	l := lexer.NewLexer(input)
+	p := parser.NewParser(l)
+	program := p.ParseProgram()
+	env := object.NewEnvironment()
+	env.CallStack().MaxDepth = 10
+
+	evaluated := evaluator.Eval(program, env)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T (%+v)", evaluated, evaluated)
+	}
+	expectedMessage := "maximum call depth exceeded: 10"
+	if errObj.Message != expectedMessage {
+		t.Errorf("wrong error message. expected=%s, got=%s", expectedMessage, errObj.Message)
+	}
+	if len(errObj.Stack) != 10 {
+		t.Errorf("wrong stack depth. expected=10, got=%d", len(errObj.Stack))
+	}
+	if env.CallStack().Depth() != 0 {
+		t.Errorf("call stack is not unwound. got=%d", env.CallStack().Depth())
+	}
+}
+
+func TestErrorStackTrace(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedLine  int
+		expectedTrace string
+	}{
+		{
+			"トップレベルのエラーは発生した行を返す",
+			`DIM a = 1
+DIM b = a + TRUE`,
+			2,
+			"\tat <main> (line 2)",
+		},
+		{
+			"関数内のエラーは呼び出し履歴を返す",
+			`FUNCTION inner(n)
+	DIM y = n + TRUE
+	RESULT = y
+FEND
+FUNCTION outer(n)
+	RESULT = inner(n)
+FEND
+outer(1)`,
+			2,
+			"\tat inner (line 2)\n\tat outer (line 6)\n\tat <main> (line 8)",
+		},
+		{
+			"ループ内のエラーも呼び出し元に伝播する",
+			`FUNCTION fn()
+	FOR n = 0 TO 2
+		DIM y = n + TRUE
+	NEXT
+	RESULT = 0
+FEND
+fn()`,
+			3,
+			"\tat fn (line 3)\n\tat <main> (line 7)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Fatalf("no error object returned. got=%T (%+v)", evaluated, evaluated)
+			}
+			if errObj.Line != tt.expectedLine {
+				t.Errorf("wrong error line. expected=%d, got=%d", tt.expectedLine, errObj.Line)
+			}
+			if errObj.Trace != tt.expectedTrace {
+				t.Errorf("wrong stack trace. expected=%q, got=%q", tt.expectedTrace, errObj.Trace)
+			}
+		})
+	}
+}
+
+func TestArithmeticErrors(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		expectedMessage string
+	}{
+		{
+			"0除算はエラーになる",
+			"1 / 0",
+			"division by zero: 1 / 0",
+		},
+		{
+			"0での剰余はエラーになる",
+			"DIM x = 5\nx MOD 0",
+			"division by zero: 5 MOD 0",
+		},
+		{
+			"関数の引数の数が異なる場合はエラーになる",
+			`FUNCTION fn(x)
+	RESULT = x
+FEND
+fn()`,
+			"wrong number of arguments to `fn`. got=0, want=1",
+		},
+		{
+			"配列の範囲外への代入はエラーになる",
+			`DIM array[1]
+array[2] = 1`,
+			"index out of range: array[2]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Fatalf("no error object returned. got=%T (%+v)", evaluated, evaluated)
+			}
+			if errObj.Message != tt.expectedMessage {
+				t.Errorf("wrong error message. expected=%s, got=%s", tt.expectedMessage, errObj.Message)
+			}
+		})
+	}
+}
+
+func TestIntegerOverflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected float64
+	}{
+		{
+			"足し算で桁あふれする場合は実数になる",
+			"9223372036854775807 + 1",
+			9223372036854775808,
+		},
+		{
+			"引き算で桁あふれする場合は実数になる",
+			"-9223372036854775807 - 2",
+			-9223372036854775809,
+		},
+		{
+			"掛け算で桁あふれする場合は実数になる",
+			"9223372036854775807 * 2",
+			18446744073709551614,
+		},
+		{
+			"実数と整数の計算は実数になる",
+			"(9223372036854775807 + 1) / 2",
+			4611686018427387904,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			testFloatObject(t, evaluated, tt.expected)
+		})
+	}
+}
+
+func testFloatObject(t *testing.T, obj object.Object, expected float64) bool {
+	result, ok := obj.(*object.Float)
+	if !ok {
+		t.Errorf("object is not Float. got=%T (%+v)", obj, obj)
+		return false
+	}
+	if result.Value != expected {
+		t.Errorf("object has wrong value. got=%g, want=%g", result.Value, expected)
+		return false
+	}
+	return true
+}
+
+func TestEvalSafely(t *testing.T) {
+	// 右辺のない前置式はpanicを起こす
+	program := &ast.Program{
+		Statements: []ast.Statement{
+			&ast.ExpressionStatement{
+				Expression: &ast.PrefixExpression{Operator: "-"},
+			},
+		},
+	}
+
+	evaluated := evaluator.EvalSafely(program, object.NewEnvironment())
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T (%+v)", evaluated, evaluated)
+	}
+	if !strings.HasPrefix(errObj.Message, "internal error: ") {
+		t.Errorf("wrong error message. got=%s", errObj.Message)
+	}
+}
+
+func TestCaseInsensitiveIdentifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int64
+	}{
+		{
+			"変数名の大文字小文字を区別しない",
+			`DIM Foo = 1
+foo`,
+			1,
+		},
+		{
+			"大文字小文字が異なる変数名に代入できる",
+			`DIM Foo = 1
+FOO = 2
+foo`,
+			2,
+		},
+		{
+			"関数名の大文字小文字を区別しない",
+			`FUNCTION AddOne(x)
+	RESULT = X + 1
+FEND
+addone(1)`,
+			2,
+		},
+		{
+			"ループ変数の大文字小文字を区別しない",
+			`DIM sum = 0
+FOR i = 1 TO 3
+	SUM = Sum + I
+NEXT
+sum`,
+			6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testIntegerObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
+
+func TestConstAndPublic(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{
+			"定数には代入できない",
+			`CONST val = 5
+val = 10`,
+			"cannot assign to constant: val",
+		},
+		{
+			"定数の配列の要素には代入できない",
+			`CONST array[] = 1, 2, 3
+array[0] = 10`,
+			"cannot assign to constant: array",
+		},
+		{
+			"定数を変数として再宣言できない",
+			`CONST val = 5
+DIM val = 10`,
+			"cannot assign to constant: val",
+		},
+		{
+			"関数内で宣言したPUBLIC変数を外から参照できる",
+			`PROCEDURE init()
+	PUBLIC val = 5
+FEND
+init()
+val`,
+			5,
+		},
+		{
+			"関数内からPUBLIC変数を書き換えられる",
+			`PUBLIC count = 0
+PROCEDURE inc()
+	count = count + 1
+FEND
+inc()
+inc()
+count`,
+			2,
+		},
+		{
+			"OPTION EXPLICITがなければ代入で変数が宣言される",
+			`val = 5
+val`,
+			5,
+		},
+		{
+			"OPTION EXPLICITがあると未宣言の変数に代入できない",
+			`OPTION EXPLICIT
+val = 5`,
+			"identifier is not defined: val",
+		},
+		{
+			"未知のOPTIONはエラーになる",
+			`OPTION UNKNOWN`,
+			"unknown option: UNKNOWN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			switch expected := tt.expected.(type) {
+			case int:
+				testIntegerObject(t, evaluated, int64(expected))
+			case string:
+				errObj, ok := evaluated.(*object.Error)
+				if !ok {
+					t.Fatalf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+				}
+				if errObj.Message != expected {
+					t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestHoisting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int64
+	}{
+		{
+			"定義より前で関数を呼び出せる",
+			`DIM val = fn(5)
+val
+FUNCTION fn(x)
+	RESULT = x * 2
+FEND`,
+			10,
+		},
+		{
+			"定義より前でPROCEDUREを呼び出せる",
+			`PUBLIC count = 0
+inc()
+count
+PROCEDURE inc()
+	count = count + 1
+FEND`,
+			1,
+		},
+		{
+			"後で宣言したPUBLIC変数を関数から参照できる",
+			`FUNCTION get()
+	RESULT = val
+FEND
+DIM got = get()
+got
+PUBLIC val = 3`,
+			3,
+		},
+		{
+			"後で宣言した定数を参照できる",
+			`DIM val = LIMIT + 1
+val
+CONST LIMIT = 9`,
+			10,
+		},
+		{
+			"宣言の値に関数の戻り値を使える",
+			`CONST LIMIT = twice(4)
+LIMIT
+FUNCTION twice(x)
+	RESULT = x * 2
+FEND`,
+			8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testIntegerObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
+
+func TestHashTableCasecare(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{
+			"デフォルトではキーの大文字小文字を区別しない",
+			`HASHTBL hash
+hash["Key"] = 5
+hash["KEY"]`,
+			5,
+		},
+		{
+			"デフォルトでは大文字小文字の異なるキーへの代入は上書きになる",
+			`HASHTBL hash
+hash["Key"] = 5
+hash["KEY"] = 10
+hash["key"]`,
+			10,
+		},
+		{
+			"デフォルトでは上書きしても最初のキーの綴りを保持する",
+			`HASHTBL hash = HASH_SORT
+hash["Key"] = 5
+hash["KEY"] = 10
+hash[0, HASH_KEY]`,
+			"Key",
+		},
+		{
+			"デフォルトでは大文字小文字の異なるキーの存在を確認できる",
+			`HASHTBL hash
+hash["Key"] = 5
+hash["kEY", HASH_EXISTS]`,
+			true,
+		},
+		{
+			"デフォルトでは大文字小文字の異なるキーで削除できる",
+			`HASHTBL hash
+hash["Key"] = 5
+hash["KEY", HASH_REMOVE]
+hash["Key", HASH_EXISTS]`,
+			false,
+		},
+		{
+			"削除できた場合はTRUEを返す",
+			`HASHTBL hash
+hash["Key"] = 5
+hash["KEY", HASH_REMOVE]`,
+			true,
+		},
+		{
+			"HASH_CASECAREではキーの大文字小文字を区別する",
+			`HASHTBL hash = HASH_CASECARE
+hash["Key"] = 5
+hash["KEY"]`,
+			nil,
+		},
+		{
+			"HASH_CASECAREでは大文字小文字の異なるキーで削除できない",
+			`HASHTBL hash = HASH_CASECARE
+hash["Key"] = 5
+hash["KEY", HASH_REMOVE]
+hash["Key", HASH_EXISTS]`,
+			true,
+		},
+		{
+			"HASH_CASECAREとHASH_SORTを組み合わせて大文字小文字の異なるキーを列挙する",
+			`HASHTBL hash = HASH_CASECARE OR HASH_SORT
+hash["a"] = 1
+hash["A"] = 2
+hash[0, HASH_KEY] + hash[1, HASH_KEY]`,
+			"Aa",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			switch expected := tt.expected.(type) {
+			case int:
+				testIntegerObject(t, evaluated, int64(expected))
+			case bool:
+				testBoolenObject(t, evaluated, expected)
+			case string:
+				testStringObject(t, evaluated, expected)
+			default:
+				testNullObject(t, evaluated)
+			}
+		})
+	}
+}
+
+func TestLogicalOperators(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"真偽値同士のOR", "FALSE OR TRUE", true},
+		{"真偽値同士のAND", "TRUE AND FALSE", false},
+		{"真偽値同士のXOR", "TRUE XOR TRUE", false},
+		{"比較式同士のAND", "1 < 2 AND 2 < 3", true},
+		{"整数同士のORはビット演算になる", "1 OR 2", 3},
+		{"整数同士のANDはビット演算になる", "6 AND 3", 2},
+		{"整数同士のXORはビット演算になる", "6 XOR 3", 5},
+		{"組み込み定数同士のOR", "HASH_CASECARE OR HASH_SORT", 12288},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			switch expected := tt.expected.(type) {
+			case int:
+				testIntegerObject(t, evaluated, int64(expected))
+			case bool:
+				testBoolenObject(t, evaluated, expected)
+			}
+		})
+	}
+}
+
+func TestHashTableEnumeration(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{
+			"HASH_SORTなしでは挿入順に列挙する",
+			`HASHTBL hash
+hash["c"] = 1
+hash["a"] = 2
+hash["b"] = 3
+hash[0, HASH_KEY] + hash[1, HASH_KEY] + hash[2, HASH_KEY]`,
+			"cab",
+		},
+		{
+			"HASH_SORTではキーの昇順に列挙する",
+			`HASHTBL hash = HASH_SORT
+hash["c"] = 1
+hash["a"] = 2
+hash["b"] = 3
+hash[0, HASH_KEY] + hash[1, HASH_KEY] + hash[2, HASH_KEY]`,
+			"abc",
+		},
+		{
+			"削除したペアは列挙されない",
+			`HASHTBL hash
+hash["c"] = 1
+hash["a"] = 2
+hash["b"] = 3
+hash["a", HASH_REMOVE]
+hash[1, HASH_VAL]`,
+			3,
+		},
+		{
+			"範囲外の順列番号はエラーになる",
+			`HASHTBL hash
+hash["a"] = 1
+hash[1, HASH_KEY]`,
+			"hash index out of range: 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			switch expected := tt.expected.(type) {
+			case int:
+				testIntegerObject(t, evaluated, int64(expected))
+			case string:
+				if errObj, ok := evaluated.(*object.Error); ok {
+					if errObj.Message != expected {
+						t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+					}
+					return
+				}
+				testStringObject(t, evaluated, expected)
+			}
+		})
+	}
+}
+
+func TestHashTableInitialValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{
+			"キーと値の組で連想配列を初期化できる",
+			`HASHTBL hash = "a", 1, "b", 2
+hash["b"]`,
+			2,
+		},
+		{
+			"初期化した連想配列も大文字小文字を区別しない",
+			`HASHTBL hash = "Key", 1
+hash["KEY"]`,
+			1,
+		},
+		{
+			"オプションを指定して初期化できる",
+			`HASHTBL hash = HASH_CASECARE, "Key", 1
+hash["KEY", HASH_EXISTS]`,
+			false,
+		},
+		{
+			"LENGTHで連想配列のペアの数を返す",
+			`HASHTBL hash = "a", 1, "b", 2
+hash["c"] = 3
+LENGTH(hash)`,
+			3,
+		},
+		{
+			"配列の要素に連想配列を入れられる",
+			`HASHTBL first = "a", 1
+HASHTBL second = "a", 2
+DIM array[] = first, second
+array[1]["a"]`,
+			2,
+		},
+		{
+			"配列の要素の連想配列に代入できる",
+			`HASHTBL first = "a", 1
+DIM array[] = first
+array[0]["a"] = 10
+array[0]["a"]`,
+			10,
+		},
+		{
+			"連想配列の値に連想配列を入れられる",
+			`HASHTBL child = "name", "uwsc"
+HASHTBL hash
+hash["child"] = child
+hash["child"]["name"]`,
+			"uwsc",
+		},
+		{
+			"入れ子の連想配列に代入できる",
+			`HASHTBL child
+HASHTBL hash = "child", child
+hash["child"]["name"] = "go"
+hash["child"]["name"]`,
+			"go",
+		},
+		{
+			"配列の添字に変数を使って代入できる",
+			`DIM array[2]
+DIM i = 1
+array[i] = 5
+array[1]`,
+			5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testExpectedObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
+
+func TestHashTableInspect(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"文字列", `HASHTBL hash = "a", "x"`, `"a", "x"`},
+		{"ダブルクォートを含む文字列", `HASHTBL hash = "a", "say ""hi"""`, `"a", "say ""hi"""`},
+		{"整数", `HASHTBL hash = "a", -1`, `"a", -1`},
+		{"小数", `HASHTBL hash = "a", VAL("1.5")`, `"a", VAL("1.5")`},
+		{"真偽値", `HASHTBL hash = "a", TRUE, "b", FALSE`, `"a", TRUE, "b", FALSE`},
+		{"EMPTYとNULL", `HASHTBL hash = "a", EMPTY, "b", NULL`, `"a", EMPTY, "b", NULL`},
+		{"数値のキー", `HASHTBL hash = 1, "x"`, `1, "x"`},
+		{"オプション", `HASHTBL hash = HASH_CASECARE OR HASH_SORT, "b", 1, "a", 2`, `HASH_CASECARE OR HASH_SORT, "a", 2, "b", 1`},
+		{"空の連想配列", `HASHTBL hash`, ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluated := testEval(tt.input + "\nhash")
+			if evaluated.Inspect() != tt.expected {
+				t.Fatalf("hash.Inspect() wrong. expected=%q, got=%q", tt.expected, evaluated.Inspect())
+			}
+			if tt.expected == "" {
+				return
+			}
+
+			// NOTE: Inspectの結果をHASHTBLの初期値として読み戻せる
+			reparsed := testEval("HASHTBL hash = " + evaluated.Inspect() + "\nhash")
+			if reparsed.Inspect() != tt.expected {
+				t.Errorf("reparsed hash.Inspect() wrong. expected=%q, got=%q", tt.expected, reparsed.Inspect())
+			}
+		})
+	}
+}
+
+func TestHashTableInspect_配列と連想配列の値(t *testing.T) {
+	input := `HASHTBL child = "d", TRUE
+DIM arr[] = "y", 2
+HASHTBL hash = "c", child, "f", arr
+hash`
+	// NOTE: 配列と連想配列の値は読み戻せない表示用の形式になる
+	expected := `"c", {"d", TRUE}, "f", ["y", 2]`
+
+	if evaluated := testEval(input); evaluated.Inspect() != expected {
+		t.Errorf("hash.Inspect() wrong. expected=%q, got=%q", expected, evaluated.Inspect())
+	}
+}
+
+// 期待値の型に応じて評価結果を検証する
+func testExpectedObject(t *testing.T, obj object.Object, expected interface{}) bool {
+	switch expected := expected.(type) {
+	case int:
+		return testIntegerObject(t, obj, int64(expected))
+	case float64:
+		return testFloatObject(t, obj, expected)
+	case bool:
+		return testBoolenObject(t, obj, expected)
+	case string:
+		return testStringObject(t, obj, expected)
+	case *object.Error:
+		errObj, ok := obj.(*object.Error)
+		if !ok {
+			t.Errorf("object is not Error. got=%T (%+v)", obj, obj)
+			return false
+		}
+		if errObj.Message != expected.Message {
+			t.Errorf("wrong error message. expected=%q, got=%q", expected.Message, errObj.Message)
+			return false
+		}
+		return true
+	default:
+		return testNullObject(t, obj)
+	}
+}
+
+func TestImplicitConversion(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"文字列と数値の足し算は文字列として連結する", `"1" + 1`, "11"},
+		{"数値と文字列の足し算は文字列として連結する", `1 + "a"`, "1a"},
+		{"文字列と数値の引き算は数値に変換して計算する", `"10" - 3`, 7},
+		{"文字列同士の掛け算は数値に変換して計算する", `"2" * "3"`, 6},
+		{"文字列と数値の割り算は数値に変換して計算する", `"7" / 2`, 3},
+		{"小数の文字列は実数に変換する", `"1.5" * 2`, 3.0},
+		{"数値と数値に変換できる文字列を比較する", `"10" = 10`, true},
+		{"数値と数値に変換できる文字列の大小を比較する", `"9" < 10`, true},
+		{"変数に入った文字列を数値として計算する", "a = \"5\"\na * 2", 10},
+		{"数値に変換できない文字列との引き算はエラーになる", `"a" - 1`, &object.Error{Message: "type mismatch: STRING - INTEGER"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testExpectedObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
+
+func TestStringComparison(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"同じ文字列は等しい", `"abc" = "abc"`, true},
+		{"大文字小文字を区別せずに比較する", `"abc" = "ABC"`, true},
+		{"異なる文字列は等しくない", `"abc" <> "abd"`, true},
+		{"辞書順で小さい", `"abc" < "abd"`, true},
+		{"大文字小文字を区別せずに大小を比較する", `"B" > "a"`, true},
+		{"前方が一致する短い文字列は小さい", `"ab" <= "abc"`, true},
+		{"同じ文字列は以上", `"ab" >= "AB"`, true},
+		{"OPTION_SAMESTRの場合は大文字小文字を区別する", "OPTION SAMESTR\n\"abc\" = \"ABC\"", false},
+		{"OPTION_SAMESTRの場合は大文字が小さい", "OPTION SAMESTR\n\"B\" < \"a\"", true},
+		{
+			"OPTION_SAMESTRは関数の中の比較にも適用される",
+			`OPTION SAMESTR
+FUNCTION same(a, b)
+	RESULT = a = b
+FEND
+same("a", "A")`,
+			false,
+		},
+		{"数値に変換できない文字列と数値は文字列として比較する", `"abc" = 1`, false},
+		{"EMPTYと空文字は等しい", `EMPTY = ""`, true},
+		{"EMPTYと0は等しい", `0 = EMPTY`, true},
+		{"EMPTYとFALSEは等しい", `EMPTY = FALSE`, true},
+		{"EMPTY同士は等しい", `EMPTY = EMPTY`, true},
+		{"EMPTYと文字列は等しくない", `EMPTY <> "a"`, true},
+		{"EMPTYは文字列と連結すると空文字として扱う", `EMPTY + "a"`, "a"},
+		{"初期化されていない配列の要素はEMPTYと等しい", "DIM array[1]\nEMPTY = array[0]", true},
+		{"NULL同士は等しい", `NULL = NULL`, true},
+		{"NULLと0は等しくない", `NULL = 0`, false},
+		{"NULLとEMPTYは等しくない", `NULL <> EMPTY`, true},
+		{"NULLの大小比較はエラーになる", `NULL < 1`, &object.Error{Message: "unknown operator: NULL < INTEGER"}},
+		{"真偽値同士を比較する", `TRUE = TRUE`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testExpectedObject(t, testEval(tt.input), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package evaluator
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// コメントや空行、並び順を保つために行単位で保持するINIファイル
+type iniFile struct {
+	lines    []string
+	encoding string
+	newline  string
+}
+
+// 新しいファイルとASCIIのみのファイルに使う文字コード
+// NOTE: ASCIIのみのファイルはUTF-8とShift_JISを区別できないため、どちらもWindowsのINIファイルと同じShift_JISにする
+const iniDefaultEncoding = ENCODING_SJIS
+
+func readIniFile(path string) (*iniFile, error) {
+	ini := &iniFile{lines: []string{}, encoding: iniDefaultEncoding, newline: "\r\n"}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ini, nil
+		}
+		return nil, err
+	}
+	text, enc, err := decodeText(b)
+	if err != nil {
+		return nil, err
+	}
+	ini.encoding = enc
+	if enc == ENCODING_UTF8 && isASCII(b) {
+		ini.encoding = iniDefaultEncoding
+	}
+	if strings.Contains(text, "\n") && !strings.Contains(text, "\r\n") {
+		ini.newline = "\n"
+	}
+	ini.lines = splitLines(text)
+	return ini, nil
+}
+
+func (ini *iniFile) write(path string) error {
+	text := strings.Join(ini.lines, ini.newline)
+	if len(ini.lines) > 0 {
+		text += ini.newline
+	}
+	b, err := encodeText(text, ini.encoding)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+func isIniComment(line string) bool {
+	return line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#")
+}
+
+// [セクション]の行の場合はセクション名を返す
+func parseIniSection(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+	return strings.TrimSpace(line[1 : len(line)-1]), true
+}
+
+func isASCII(b []byte) bool {
+	for _, c := range b {
+		if c >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// キー=値の行を、値の前(キーと=と空白)、値、値の後ろ(空白と行末のコメント)に分ける
+// NOTE: 空白の後ろの;と#はダブルクォートの外にある場合だけ行末のコメントとして扱う
+func splitIniKeyValue(line string) (string, string, string, bool) {
+	if isIniComment(strings.TrimSpace(line)) {
+		return "", "", "", false
+	}
+	i := strings.Index(line, "=")
+	if i < 0 {
+		return "", "", "", false
+	}
+	start := len(line) - len(strings.TrimLeft(line[i+1:], " \t"))
+	end := len(line)
+	inQuote := false
+	for j := start; j < len(line) && end == len(line); j++ {
+		switch c := line[j]; {
+		case c == '"':
+			inQuote = !inQuote
+		case (c == ';' || c == '#') && !inQuote && (line[j-1] == ' ' || line[j-1] == '\t'):
+			end = j
+		}
+	}
+	end = len(strings.TrimRight(line[:end], " \t"))
+	if end < start {
+		end = start
+	}
+	return line[:start], line[start:end], line[end:], true
+}
+
+func isIniQuoted(value string) bool {
+	return len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`)
+}
+
+// キー=値の行の場合はキーと値を返す。値を囲むダブルクォートと行末のコメントは除く
+func parseIniKeyValue(line string) (string, string, bool) {
+	prefix, value, _, ok := splitIniKeyValue(line)
+	if !ok {
+		return "", "", false
+	}
+	key := strings.TrimSpace(prefix[:strings.Index(prefix, "=")])
+	if isIniQuoted(value) {
+		value = value[1 : len(value)-1]
+	}
+	return key, value, true
+}
+
+// セクションの範囲(見出しの行と、次のセクションの直前の行)を返す。見つからない場合は-1を返す
+func (ini *iniFile) sectionRange(section string) (int, int) {
+	start := -1
+	for i, line := range ini.lines {
+		name, ok := parseIniSection(line)
+		if !ok {
+			continue
+		}
+		if start >= 0 {
+			return start, i
+		}
+		// NOTE: セクション名とキーは大文字小文字を区別しない
+		if strings.EqualFold(name, section) {
+			start = i
+		}
+	}
+	return start, len(ini.lines)
+}
+
+func (ini *iniFile) sections() []string {
+	sections := []string{}
+	for _, line := range ini.lines {
+		if name, ok := parseIniSection(line); ok {
+			sections = append(sections, name)
+		}
+	}
+	return sections
+}
+
+func (ini *iniFile) keys(section string) []string {
+	keys := []string{}
+	start, end := ini.sectionRange(section)
+	if start < 0 {
+		return keys
+	}
+	for _, line := range ini.lines[start+1 : end] {
+		if key, _, ok := parseIniKeyValue(line); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// キーの行番号を返す。見つからない場合は-1を返す
+func (ini *iniFile) keyIndex(section, key string) int {
+	start, end := ini.sectionRange(section)
+	if start < 0 {
+		return -1
+	}
+	for i := start + 1; i < end; i++ {
+		if k, _, ok := parseIniKeyValue(ini.lines[i]); ok && strings.EqualFold(k, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (ini *iniFile) get(section, key string) (string, bool) {
+	i := ini.keyIndex(section, key)
+	if i < 0 {
+		return "", false
+	}
+	_, value, _ := parseIniKeyValue(ini.lines[i])
+	return value, true
+}
+
+// 既存のキーは同じ行の値だけを書き換え、新しいキーはセクションの最後のキーの後ろに追加する
+// NOTE: 既存の行のキーの表記、=の前後の空白、値を囲むダブルクォート、行末のコメントは保つ
+func (ini *iniFile) set(section, key, value string) {
+	if i := ini.keyIndex(section, key); i >= 0 {
+		prefix, old, suffix, _ := splitIniKeyValue(ini.lines[i])
+		if isIniQuoted(old) {
+			value = `"` + value + `"`
+		}
+		if old != value {
+			ini.lines[i] = prefix + value + suffix
+		}
+		return
+	}
+	line := key + "=" + value
+	start, end := ini.sectionRange(section)
+	if start < 0 {
+		if len(ini.lines) > 0 && strings.TrimSpace(ini.lines[len(ini.lines)-1]) != "" {
+			ini.lines = append(ini.lines, "")
+		}
+		ini.lines = append(ini.lines, "["+section+"]", line)
+		return
+	}
+	insertAt := start + 1
+	for i := start + 1; i < end; i++ {
+		if strings.TrimSpace(ini.lines[i]) != "" {
+			insertAt = i + 1
+		}
+	}
+	ini.lines = append(ini.lines[:insertAt], append([]string{line}, ini.lines[insertAt:]...)...)
+}
+
+func (ini *iniFile) deleteKey(section, key string) bool {
+	i := ini.keyIndex(section, key)
+	if i < 0 {
+		return false
+	}
+	ini.lines = append(ini.lines[:i], ini.lines[i+1:]...)
+	return true
+}
+
+func (ini *iniFile) deleteSection(section string) bool {
+	start, end := ini.sectionRange(section)
+	if start < 0 {
+		return false
+	}
+	ini.lines = append(ini.lines[:start], ini.lines[end:]...)
+	return true
+}
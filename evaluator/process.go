@@ -0,0 +1,186 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/sam8helloworld/uwscgo/object"
+)
+
+// EXECで実行できるコマンドを制限する。nilを指定すると制限を解除する
+// NOTE: コマンドは設定した時点のPATHで絶対パスにし、実行時も絶対パスで比較する
+// 制限している間はDOSCMDとSETENVによるPATHの変更はエラーになる
+func SetAllowedCommands(env *object.Environment, commands []string) error {
+	if commands == nil {
+		env.SetAllowedCommands(nil)
+		return nil
+	}
+	paths := []string{}
+	for _, c := range commands {
+		path, err := lookPathAbs(c)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, path)
+	}
+	env.SetAllowedCommands(paths)
+	return nil
+}
+
+func restrictsCommands(env *object.Environment) bool {
+	return env.AllowedCommands() != nil
+}
+
+// 実行するコマンドのパスと、実行が許可されているかを返す
+// NOTE: 制限している場合は絶対パスを返す。制限していない場合は名前のまま返す
+func allowsCommand(env *object.Environment, name string) (string, bool) {
+	if !restrictsCommands(env) {
+		return name, true
+	}
+	path, err := lookPathAbs(name)
+	if err != nil {
+		return "", false
+	}
+	for _, allowed := range env.AllowedCommands() {
+		if path == allowed {
+			return path, true
+		}
+	}
+	return path, false
+}
+
+func lookPathAbs(name string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(path)
+}
+
+// DOSCMDが使うシェル
+func shellCommand(command string) []string {
+	if runtime.GOOS == "windows" {
+		return []string{"cmd", "/c", command}
+	}
+	return []string{"sh", "-c", command}
+}
+
+// 空白で区切る。シングルクォートとダブルクォートで囲んだ部分は区切らない
+func splitCommandLine(line string) []string {
+	fields := []string{}
+	var field strings.Builder
+	inField := false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				field.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inField = true
+		case r == ' ' || r == '\t':
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteRune(r)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+type commandResult struct {
+	output   string
+	exitCode int
+}
+
+// タイムアウトを指定した場合はctxに期限を付ける
+func commandContext(ctx context.Context, timeout float64) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	d := time.Duration(math.MaxInt64)
+	if timeout < float64(math.MaxInt64)/float64(time.Second) {
+		d = time.Duration(timeout * float64(time.Second))
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// コマンドを実行して終了を待つ。タイムアウトした場合は終了コードを-1にする
+// NOTE: ctxがキャンセルされた場合はコマンドを止めてctxのエラーを返す
+func runCommand(ctx context.Context, argv []string, dir string, timeout float64) (commandResult, error) {
+	runCtx, cancel := commandContext(ctx, timeout)
+	defer cancel()
+
+	// NOTE: パイプにすると子プロセスが出力を開いている間は終了を待ち続けるため、一時ファイルに出力する
+	out, err := os.CreateTemp("", "uwscgo-exec-*")
+	if err != nil {
+		return commandResult{}, err
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	cmd := exec.CommandContext(runCtx, argv[0], argv[1:]...)
+	cmd.Dir = dir
+	cmd.Stdout = out
+	cmd.Stderr = out
+	err = cmd.Run()
+
+	b, readErr := os.ReadFile(out.Name())
+	if readErr != nil {
+		return commandResult{}, readErr
+	}
+	output, _, decodeErr := decodeText(b)
+	if decodeErr != nil {
+		output = string(b)
+	}
+	result := commandResult{output: output}
+	if ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+	if runCtx.Err() != nil {
+		result.exitCode = -1
+		return result, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.exitCode = exitErr.ExitCode()
+		return result, nil
+	}
+	return result, err
+}
+
+// コマンドを起動して終了を待たずにプロセスIDを返す
+// NOTE: ctxがキャンセルされた場合やタイムアウトした場合は、起動したコマンドを止める
+func startCommand(ctx context.Context, argv []string, dir string, timeout float64) (int, error) {
+	runCtx, cancel := commandContext(ctx, timeout)
+	cmd := exec.CommandContext(runCtx, argv[0], argv[1:]...)
+	cmd.Dir = dir
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return 0, err
+	}
+	// NOTE: 終了したプロセスが残らないように回収する
+	go func() {
+		cmd.Wait()
+		cancel()
+	}()
+	return cmd.Process.Pid, nil
+}
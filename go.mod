@@ -1,3 +1,5 @@
 module github.com/sam8helloworld/uwscgo
 
 go 1.18
+
+require golang.org/x/text v0.14.0
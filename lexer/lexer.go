@@ -1,6 +1,8 @@
 package lexer
 
 import (
+	"strings"
+
 	"github.com/sam8helloworld/uwscgo/token"
 )
 
@@ -9,10 +11,11 @@ type Lexer struct {
 	position     int
 	readPosition int
 	ch           byte
+	line         int
 }
 
 func NewLexer(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{input: input, line: 1}
 	l.readChar()
 	return l
 }
@@ -39,6 +42,7 @@ func (l *Lexer) NextToken() token.Token {
 	var tok = token.Token{}
 
 	l.skipWhiteSpace()
+	line := l.line
 	switch l.ch {
 	case '=':
 		tok = token.Token{
@@ -144,6 +148,11 @@ func (l *Lexer) NextToken() token.Token {
 			Type:    token.COMMA,
 			Literal: string(l.ch),
 		}
+	case '.':
+		tok = token.Token{
+			Type:    token.DOT,
+			Literal: string(l.ch),
+		}
 	case '"':
 		literal := l.readString()
 		tok = token.Token{
@@ -156,6 +165,7 @@ func (l *Lexer) NextToken() token.Token {
 			Type:    token.EOL,
 			Literal: string(l.ch),
 		}
+		l.line += 1
 	case 0:
 		tok = token.Token{
 			Type:    token.EOF,
@@ -165,10 +175,12 @@ func (l *Lexer) NextToken() token.Token {
 		if isLetter(l.ch) {
 			tok.Literal = l.readIdentifier()
 			tok.Type = token.LookupIdent(tok.Literal)
+			tok.Line = line
 			return tok
 		} else if isDigit(l.ch) {
 			tok.Type = token.INT
 			tok.Literal = l.readNumber()
+			tok.Line = line
 			return tok
 		} else {
 			tok = token.Token{
@@ -178,6 +190,7 @@ func (l *Lexer) NextToken() token.Token {
 		}
 	}
 	l.readChar()
+	tok.Line = line
 	return tok
 }
 
@@ -203,15 +216,23 @@ func (l *Lexer) skipWhiteSpace() {
 	}
 }
 
+// NOTE: 文字列の中の""は"1文字として扱う
 func (l *Lexer) readString() string {
 	position := l.position + 1
 	for {
 		l.readChar()
+		if l.ch == '"' && l.peekChar() == '"' {
+			l.readChar()
+			continue
+		}
 		if l.ch == '"' || l.ch == 0 {
 			break
 		}
+		if l.ch == '\n' {
+			l.line += 1
+		}
 	}
-	return l.input[position:l.position]
+	return strings.ReplaceAll(l.input[position:l.position], `""`, `"`)
 }
 
 func isLetter(ch byte) bool {
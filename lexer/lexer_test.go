@@ -427,6 +427,20 @@ func TestNextToken_文字列(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "連続したダブルクォートは1つにする",
+			input: `"say ""hi""" ""`,
+			expected: []token.Token{
+				{
+					Type:    token.STRING,
+					Literal: `say "hi"`,
+				},
+				{
+					Type:    token.STRING,
+					Literal: "",
+				},
+			},
+		},
 	}
 
 	testToken(t, tests)
@@ -690,3 +704,19 @@ NEXT`,
 
 	testToken(t, tests)
 }
+
+func TestNextToken_行番号(t *testing.T) {
+	input := `DIM val = 5
+val = val + 1
+
+val`
+	expected := []int{1, 1, 1, 1, 1, 2, 2, 2, 2, 2, 2, 3, 4}
+
+	sut := lexer.NewLexer(input)
+	for i, line := range expected {
+		got := sut.NextToken()
+		if got.Line != line {
+			t.Fatalf("tokens[%d] - line wrong. expected=%d, got=%d (%q)", i, line, got.Line, got.Literal)
+		}
+	}
+}
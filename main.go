@@ -1,11 +1,27 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/sam8helloworld/uwscgo/repl"
+	"github.com/sam8helloworld/uwscgo/runner"
 )
 
 func main() {
-	repl.Start(os.Stdin, os.Stdout)
+	if len(os.Args) < 2 {
+		repl.Start(os.Stdin, os.Stdout)
+		return
+	}
+
+	f, err := os.Open(os.Args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	ok := runner.Run(f, os.Stderr)
+	f.Close()
+	if !ok {
+		os.Exit(1)
+	}
 }
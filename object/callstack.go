@@ -0,0 +1,49 @@
+package object
+
+// 再帰呼び出しの上限のデフォルト値
+const DefaultMaxCallDepth = 1000
+
+type CallFrame struct {
+	Name string // 呼び出された関数名
+	Line int    // 呼び出し元の行番号
+}
+
+type CallStack struct {
+	frames   []CallFrame
+	MaxDepth int
+}
+
+func NewCallStack() *CallStack {
+	return &CallStack{
+		frames:   []CallFrame{},
+		MaxDepth: DefaultMaxCallDepth,
+	}
+}
+
+// 上限を超える場合は積まずにfalseを返す
+func (cs *CallStack) Push(frame CallFrame) bool {
+	if cs.MaxDepth > 0 && len(cs.frames) >= cs.MaxDepth {
+		return false
+	}
+	cs.frames = append(cs.frames, frame)
+	return true
+}
+
+func (cs *CallStack) Pop() {
+	if len(cs.frames) > 0 {
+		cs.frames = cs.frames[:len(cs.frames)-1]
+	}
+}
+
+func (cs *CallStack) Depth() int {
+	return len(cs.frames)
+}
+
+// 内側の呼び出しから順に並べたコピーを返す
+func (cs *CallStack) Frames() []CallFrame {
+	frames := make([]CallFrame, len(cs.frames))
+	for i, f := range cs.frames {
+		frames[len(cs.frames)-1-i] = f
+	}
+	return frames
+}
@@ -1,5 +1,12 @@
 package object
 
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+)
+
 type BindedObjectType string
 
 const (
@@ -9,9 +16,11 @@ const (
 )
 
 func NewEnclosedEnvironment(outer *Environment) *Environment {
-	env := NewEnvironment()
-	env.outer = outer
-	return env
+	return &Environment{
+		store:   map[string]*BindedObject{},
+		outer:   outer,
+		runtime: outer.runtime,
+	}
 }
 
 type BindedObject struct {
@@ -21,20 +30,129 @@ type BindedObject struct {
 }
 
 type Environment struct {
-	store map[string]*BindedObject
-	outer *Environment
+	store   map[string]*BindedObject
+	outer   *Environment
+	runtime *runtime // 内側の環境と同じものを指す
+}
+
+// スクリプト全体で共有する状態。いつ設定しても全ての環境に反映される
+type runtime struct {
+	callStack       *CallStack       // 関数呼び出しの履歴
+	options         map[string]bool  // OPTIONの設定
+	ctx             context.Context  // スクリプトを中断するためのコンテキスト
+	random          *rand.Rand       // RANDOMが使う乱数生成器
+	now             func() time.Time // 現在時刻を返す関数
+	allowedCommands []string         // EXECで実行できるコマンドの絶対パス。nilの場合は制限しない
+	openFiles       []*File          // FOPENで開いてまだFCLOSEしていないファイル
 }
 
 func NewEnvironment() *Environment {
-	s := make(map[string]*BindedObject)
 	return &Environment{
-		store: s,
+		store: map[string]*BindedObject{},
 		outer: nil,
+		runtime: &runtime{
+			callStack: NewCallStack(),
+			options:   map[string]bool{},
+			ctx:       context.Background(),
+			random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+			now:       time.Now,
+		},
+	}
+}
+
+// スクリプト全体で共有される最も外側の環境を返す
+func (e *Environment) Global() *Environment {
+	env := e
+	for env.outer != nil {
+		env = env.outer
+	}
+	return env
+}
+
+func (e *Environment) SetOption(name string, enabled bool) {
+	e.runtime.options[normalizeName(name)] = enabled
+}
+
+func (e *Environment) Option(name string) bool {
+	return e.runtime.options[normalizeName(name)]
+}
+
+func (e *Environment) SetContext(ctx context.Context) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	e.runtime.ctx = ctx
+}
+
+func (e *Environment) Context() context.Context {
+	return e.runtime.ctx
+}
+
+// RANDOMの乱数の種を設定する。テストで結果を再現するために使う
+func (e *Environment) SeedRandom(seed int64) {
+	e.runtime.random = rand.New(rand.NewSource(seed))
+}
+
+func (e *Environment) Random() *rand.Rand {
+	return e.runtime.random
+}
+
+// 現在時刻を返す関数を設定する。テストで時刻を固定するために使う。nilの場合はtime.Nowに戻す
+func (e *Environment) SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
 	}
+	e.runtime.now = now
+}
+
+func (e *Environment) Now() time.Time {
+	return e.runtime.now()
+}
+
+// EXECで実行できるコマンドの絶対パスを設定する。nilを指定すると制限を解除する
+// NOTE: 名前から絶対パスへの変換と実行時の確認はevaluatorで行う
+func (e *Environment) SetAllowedCommands(paths []string) {
+	if paths == nil {
+		e.runtime.allowedCommands = nil
+		return
+	}
+	e.runtime.allowedCommands = append([]string{}, paths...)
+}
+
+// 制限していない場合はnilを返す
+func (e *Environment) AllowedCommands() []string {
+	return e.runtime.allowedCommands
+}
+
+func (e *Environment) AddOpenFile(f *File) {
+	e.runtime.openFiles = append(e.runtime.openFiles, f)
+}
+
+func (e *Environment) RemoveOpenFile(f *File) {
+	for i, opened := range e.runtime.openFiles {
+		if opened == f {
+			e.runtime.openFiles = append(e.runtime.openFiles[:i], e.runtime.openFiles[i+1:]...)
+			return
+		}
+	}
+}
+
+// 開いた順に返す
+func (e *Environment) OpenFiles() []*File {
+	return append([]*File{}, e.runtime.openFiles...)
+}
+
+func (e *Environment) CallStack() *CallStack {
+	return e.runtime.callStack
+}
+
+// UWSCの識別子は大文字小文字を区別しないため、正規化した名前をキーにする
+func normalizeName(name string) string {
+	return strings.ToUpper(name)
 }
 
 func (e *Environment) Get(name string) (Object, bool) {
-	obj, ok := e.store[name]
+	obj, ok := e.store[normalizeName(name)]
 	if !ok && e.outer != nil {
 		obj, ok = e.outer.BindedObject(name)
 	}
@@ -46,7 +164,7 @@ func (e *Environment) Get(name string) (Object, bool) {
 }
 
 func (e *Environment) BindedObject(name string) (*BindedObject, bool) {
-	obj, ok := e.store[name]
+	obj, ok := e.store[normalizeName(name)]
 	if !ok && e.outer != nil {
 		obj, ok = e.outer.BindedObject(name)
 	}
@@ -54,8 +172,8 @@ func (e *Environment) BindedObject(name string) (*BindedObject, bool) {
 }
 
 func (e *Environment) Set(name string, val Object) Object {
-	e.store[name] = &BindedObject{
-		Key:    name,
+	e.store[normalizeName(name)] = &BindedObject{
+		Key:    e.originalName(name),
 		Object: val,
 		Type:   LOCAL,
 	}
@@ -63,10 +181,29 @@ func (e *Environment) Set(name string, val Object) Object {
 }
 
 func (e *Environment) SetConst(name string, val Object) Object {
-	e.store[name] = &BindedObject{
-		Key:    name,
+	e.store[normalizeName(name)] = &BindedObject{
+		Key:    e.originalName(name),
 		Object: val,
 		Type:   CONST,
 	}
 	return val
 }
+
+// どの関数からも参照できるように最も外側の環境に束縛する
+func (e *Environment) SetPublic(name string, val Object) Object {
+	global := e.Global()
+	global.store[normalizeName(name)] = &BindedObject{
+		Key:    global.originalName(name),
+		Object: val,
+		Type:   PUBLIC,
+	}
+	return val
+}
+
+// 既に束縛されている場合は最初に宣言された綴りを返す
+func (e *Environment) originalName(name string) string {
+	if obj, ok := e.store[normalizeName(name)]; ok {
+		return obj.Key
+	}
+	return name
+}
@@ -0,0 +1,89 @@
+package object
+
+// FOPENで開いたファイル。内容は行単位で保持し、FCLOSEで書き込む
+type File struct {
+	Path     string
+	Encoding string // 読み込み時に判定した文字コード(書き込み時も同じ文字コードを使う)
+	Newline  string
+	Readable bool
+	Writable bool
+	Append   bool // 既存の内容を読み込まずに末尾に追加する
+	NoCR     bool // 最終行の末尾に改行を付けない
+	Tab      bool // 列の区切りをタブにする
+	Closed   bool
+	Modified bool
+	lines    []string
+}
+
+func NewFile(path string, lines []string) *File {
+	return &File{
+		Path:    path,
+		Newline: "\r\n",
+		lines:   lines,
+	}
+}
+
+func (f *File) Type() ObjectType {
+	return FILE_OBJ
+}
+
+func (f *File) Inspect() string {
+	return "file(" + f.Path + ")"
+}
+
+func (f *File) LineCount() int {
+	return len(f.lines)
+}
+
+// 行番号は1から始まる。存在しない場合はfalseを返す
+func (f *File) Line(n int) (string, bool) {
+	if n < 1 || n > len(f.lines) {
+		return "", false
+	}
+	return f.lines[n-1], true
+}
+
+func (f *File) Lines() []string {
+	lines := make([]string, len(f.lines))
+	copy(lines, f.lines)
+	return lines
+}
+
+// 行が足りない場合は空行を追加してから書き換える
+func (f *File) SetLine(n int, line string) {
+	for len(f.lines) < n {
+		f.lines = append(f.lines, "")
+	}
+	f.lines[n-1] = line
+	f.Modified = true
+}
+
+// n行目の前に挿入する。行が足りない場合は空行を追加する
+func (f *File) InsertLine(n int, line string) {
+	for len(f.lines) < n-1 {
+		f.lines = append(f.lines, "")
+	}
+	f.lines = append(f.lines, "")
+	copy(f.lines[n:], f.lines[n-1:])
+	f.lines[n-1] = line
+	f.Modified = true
+}
+
+func (f *File) AppendLine(line string) {
+	f.lines = append(f.lines, line)
+	f.Modified = true
+}
+
+func (f *File) DeleteLine(n int) bool {
+	if n < 1 || n > len(f.lines) {
+		return false
+	}
+	f.lines = append(f.lines[:n-1], f.lines[n:]...)
+	f.Modified = true
+	return true
+}
+
+func (f *File) SetLines(lines []string) {
+	f.lines = lines
+	f.Modified = true
+}
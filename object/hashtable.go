@@ -0,0 +1,167 @@
+package object
+
+import (
+	"sort"
+	"strings"
+)
+
+type HashTable struct {
+	pairs      map[HashKey]HashPair
+	order      []HashKey // 列挙順(通常は挿入順、HASH_SORTの場合はキーの昇順)
+	IsSort     bool
+	IsCasecare bool
+}
+
+func NewHashTable(isSort, isCasecare bool) *HashTable {
+	return &HashTable{
+		pairs:      map[HashKey]HashPair{},
+		order:      []HashKey{},
+		IsSort:     isSort,
+		IsCasecare: isCasecare,
+	}
+}
+
+func (ht *HashTable) Type() ObjectType {
+	return HASHTBL_OBJ
+}
+
+// HASHTBL 変数 = の後ろに書くと読み戻せる「オプション, キー, 値, ...」の形式で返す
+// NOTE: 読み戻せるのは値が文字列、数値、真偽値、EMPTY、NULLの場合だけ。配列の値は[...]、連想配列の値は{...}で表す
+func (ht *HashTable) Inspect() string {
+	items := []string{}
+	switch {
+	case ht.IsSort && ht.IsCasecare:
+		items = append(items, "HASH_CASECARE OR HASH_SORT")
+	case ht.IsSort:
+		items = append(items, "HASH_SORT")
+	case ht.IsCasecare:
+		items = append(items, "HASH_CASECARE")
+	}
+	for _, pair := range ht.Pairs() {
+		items = append(items, inspectLiteral(pair.Key), inspectLiteral(pair.Value))
+	}
+	return strings.Join(items, ", ")
+}
+
+// HASH_CASECAREが指定されていない場合、文字列のキーは大文字小文字を区別しない
+func (ht *HashTable) hashKey(key Hashable) HashKey {
+	if s, ok := key.(*String); ok && !ht.IsCasecare {
+		return (&String{Value: strings.ToUpper(s.Value)}).HashKey()
+	}
+	return key.HashKey()
+}
+
+func (ht *HashTable) Get(key Hashable) (Object, bool) {
+	pair, ok := ht.pairs[ht.hashKey(key)]
+	if !ok {
+		return nil, false
+	}
+	return pair.Value, true
+}
+
+// 既にキーが存在する場合は最初に登録したキーの綴りと順番を保持する
+func (ht *HashTable) Set(key Hashable, val Object) {
+	if ht.pairs == nil {
+		ht.pairs = map[HashKey]HashPair{}
+	}
+	hk := ht.hashKey(key)
+	if pair, ok := ht.pairs[hk]; ok {
+		ht.pairs[hk] = HashPair{Key: pair.Key, Value: val}
+		return
+	}
+	ht.pairs[hk] = HashPair{Key: key, Value: val}
+
+	if !ht.IsSort {
+		ht.order = append(ht.order, hk)
+		return
+	}
+	i := sort.Search(len(ht.order), func(i int) bool {
+		return ht.compareKeys(ht.pairs[ht.order[i]].Key, key) > 0
+	})
+	ht.order = append(ht.order, HashKey{})
+	copy(ht.order[i+1:], ht.order[i:])
+	ht.order[i] = hk
+}
+
+// 削除した場合はtrueを返す
+func (ht *HashTable) Remove(key Hashable) bool {
+	hk := ht.hashKey(key)
+	if _, ok := ht.pairs[hk]; !ok {
+		return false
+	}
+	delete(ht.pairs, hk)
+
+	for i, k := range ht.order {
+		if k == hk {
+			ht.order = append(ht.order[:i], ht.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (ht *HashTable) Len() int {
+	return len(ht.order)
+}
+
+// 列挙順に並べたペアを返す
+func (ht *HashTable) Pairs() []HashPair {
+	pairs := make([]HashPair, 0, len(ht.order))
+	for _, hk := range ht.order {
+		pairs = append(pairs, ht.pairs[hk])
+	}
+	return pairs
+}
+
+// 範囲外の場合はfalseを返す
+func (ht *HashTable) GetPairByIndex(index int) (HashPair, bool) {
+	if index < 0 || index >= len(ht.order) {
+		return HashPair{}, false
+	}
+	return ht.pairs[ht.order[index]], true
+}
+
+// 数値同士は数値として、それ以外は文字列として比較する
+func (ht *HashTable) compareKeys(a, b Object) int {
+	ai, aok := a.(*Integer)
+	bi, bok := b.(*Integer)
+	if aok && bok {
+		switch {
+		case ai.Value < bi.Value:
+			return -1
+		case ai.Value > bi.Value:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	as, bs := a.Inspect(), b.Inspect()
+	if !ht.IsCasecare {
+		as, bs = strings.ToUpper(as), strings.ToUpper(bs)
+	}
+	return strings.Compare(as, bs)
+}
+
+// 値を式として読み戻せる形式で返す。文字列は引用符で囲み、小数はVALで表す
+func inspectLiteral(obj Object) string {
+	switch obj := obj.(type) {
+	case *String:
+		return `"` + strings.ReplaceAll(obj.Value, `"`, `""`) + `"`
+	case *Float:
+		return `VAL("` + obj.Inspect() + `")`
+	case *Boolean:
+		return strings.ToUpper(obj.Inspect())
+	case *Null:
+		return "NULL"
+	case *Array:
+		elements := []string{}
+		for _, e := range obj.Elements {
+			elements = append(elements, inspectLiteral(e))
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case *HashTable:
+		return "{" + obj.Inspect() + "}"
+	}
+	return obj.Inspect()
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
-	"sort"
 	"strconv"
 	"strings"
 
@@ -14,20 +13,23 @@ import (
 type ObjectType string
 
 const (
-	INTEGER_OBJ                       = "INTEGER"
-	NULL_OBJ                          = "NULL"
-	EMPTY_OBJ                         = "EMPTY"
-	BOOLEAN_OBJ                       = "BOOLEAN"
-	FUNCTION_OBJ                      = "FUNCTION"
-	ERROR_OBJ                         = "ERROR"
-	RESULT_VALUE_OBJ                  = "RESULT_VALUE"
-	STRING_OBJ                        = "STRING"
-	BUILTIN_FUNCTION_OBJ              = "BUILTIN_FUNCTION_OBJ"
-	BUILTIN_CONSTANT_OBJ              = "BUILTIN_CONSTANT_OBJ"
-	ARRAY_OBJ                         = "ARRAY"
-	HASHTBL_OBJ                       = "HASHTBL_OBJ"
-	BUILTIN_FUNC_RETURN_RESULT_OBJ    = "BUILTIN_FUNC_RETURN_RESULT"
-	BUILTIN_FUNC_RETURN_REFERENCE_OBJ = "BUILTIN_FUNC_RETURN_REFERENCE"
+	INTEGER_OBJ                        = "INTEGER"
+	FLOAT_OBJ                          = "FLOAT"
+	NULL_OBJ                           = "NULL"
+	EMPTY_OBJ                          = "EMPTY"
+	BOOLEAN_OBJ                        = "BOOLEAN"
+	FUNCTION_OBJ                       = "FUNCTION"
+	ERROR_OBJ                          = "ERROR"
+	RESULT_VALUE_OBJ                   = "RESULT_VALUE"
+	STRING_OBJ                         = "STRING"
+	BUILTIN_FUNCTION_OBJ               = "BUILTIN_FUNCTION_OBJ"
+	BUILTIN_CONSTANT_OBJ               = "BUILTIN_CONSTANT_OBJ"
+	ARRAY_OBJ                          = "ARRAY"
+	HASHTBL_OBJ                        = "HASHTBL_OBJ"
+	FILE_OBJ                           = "FILE"
+	BUILTIN_FUNC_RETURN_RESULT_OBJ     = "BUILTIN_FUNC_RETURN_RESULT"
+	BUILTIN_FUNC_RETURN_REFERENCE_OBJ  = "BUILTIN_FUNC_RETURN_REFERENCE"
+	BUILTIN_FUNC_RETURN_REFERENCES_OBJ = "BUILTIN_FUNC_RETURN_REFERENCES"
 )
 
 type Object interface {
@@ -47,11 +49,29 @@ func (i *Integer) Type() ObjectType {
 	return INTEGER_OBJ
 }
 
+type Float struct {
+	Value float64
+}
+
+func (f *Float) Inspect() string {
+	return strconv.FormatFloat(f.Value, 'g', -1, 64)
+}
+
+func (f *Float) Type() ObjectType {
+	return FLOAT_OBJ
+}
+
 type Error struct {
 	Message string
+	Line    int         // エラーが発生した文の行番号
+	Stack   []CallFrame // エラー発生時の呼び出し履歴(内側から順)
+	Trace   string      // 整形済みのスタックトレース
 }
 
 func (e *Error) Inspect() string {
+	if e.Trace != "" {
+		return "ERROR: " + e.Message + "\n" + e.Trace
+	}
 	return "ERROR: " + e.Message
 }
 
@@ -158,6 +178,8 @@ type BuiltinFuncArgument struct {
 
 type BuiltinFunction struct {
 	Fn func(args ...BuiltinFuncArgument) Object
+	// 環境の設定(コンテキストや乱数など)を使う関数はFnの代わりにこちらを使う
+	EnvFn func(env *Environment, args ...BuiltinFuncArgument) Object
 }
 
 func (bf *BuiltinFunction) Type() ObjectType {
@@ -244,6 +266,24 @@ func (b *BuiltinFuncReturnReference) Inspect() string {
 	return out.String()
 }
 
+// 複数の引数に書き戻す場合に使う
+type BuiltinFuncReturnReferences struct {
+	References []*BuiltinFuncReturnReference
+	Result     Object
+}
+
+func (b *BuiltinFuncReturnReferences) Type() ObjectType {
+	return BUILTIN_FUNC_RETURN_REFERENCES_OBJ
+}
+
+func (b *BuiltinFuncReturnReferences) Inspect() string {
+	references := []string{}
+	for _, r := range b.References {
+		references = append(references, "("+r.Expression.String()+"="+r.Value.Inspect()+")")
+	}
+	return "{" + strings.Join(references, ",") + ",result=" + b.Result.Inspect() + "}"
+}
+
 type HashKey struct {
 	Type  ObjectType
 	Value uint64
@@ -277,61 +317,6 @@ type HashPair struct {
 }
 
 type Hashable interface {
+	Object
 	HashKey() HashKey
 }
-
-type HashTable struct {
-	Pairs      map[HashKey]HashPair
-	IsSort     bool
-	IsCasecare bool
-}
-
-func (ht *HashTable) Type() ObjectType {
-	return HASHTBL_OBJ
-}
-
-func (ht *HashTable) Inspect() string {
-	var out bytes.Buffer
-
-	pairs := []string{}
-	for _, pair := range ht.Pairs {
-		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
-	}
-
-	out.WriteString("{")
-	out.WriteString(strings.Join(pairs, ", "))
-	out.WriteString("}")
-	return out.String()
-}
-
-func (ht *HashTable) GetPairByIndex(index int) HashPair {
-	hashKeys := []struct {
-		hashKey HashKey
-		key     string
-	}{}
-	for hk, pair := range ht.Pairs {
-		var val string
-		switch p := pair.Value.(type) {
-		case *Integer:
-			val = strconv.FormatInt(p.Value, 10)
-		case *String:
-			val = p.Value
-		case *Boolean:
-			val = strconv.FormatBool(p.Value)
-		}
-		hashKeys = append(hashKeys, struct {
-			hashKey HashKey
-			key     string
-		}{
-			hashKey: hk,
-			key:     val,
-		})
-	}
-	if ht.IsSort {
-		sort.Slice(hashKeys, func(i, j int) bool {
-			return hashKeys[i].key > hashKeys[j].key
-		})
-	}
-
-	return ht.Pairs[hashKeys[index].hashKey]
-}
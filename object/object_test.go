@@ -1,7 +1,9 @@
 package object_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/sam8helloworld/uwscgo/object"
 )
@@ -26,51 +28,131 @@ func TestStringHashKey(t *testing.T) {
 }
 
 func TestHashTableGeyPairByIndex(t *testing.T) {
-	ht := &object.HashTable{
-		Pairs: map[object.HashKey]object.HashPair{
-			{
-				Type:  object.STRING_OBJ,
-				Value: uint64(12638189399578898418),
-			}: {
-				Key: &object.String{
-					Value: "c",
-				},
-				Value: &object.Integer{
-					Value: 3,
-				},
-			},
-			{
-				Type:  object.STRING_OBJ,
-				Value: uint64(12638187200555641996),
-			}: {
-				Key: &object.String{
-					Value: "a",
-				},
-				Value: &object.Integer{
-					Value: 1,
-				},
-			},
-			{
-				Type:  object.STRING_OBJ,
-				Value: uint64(12638190499090526629),
-			}: {
-				Key: &object.String{
-					Value: "b",
-				},
-				Value: &object.Integer{
-					Value: 2,
-				},
-			},
-		},
-		IsSort: true,
-	}
-
-	pair := ht.GetPairByIndex(1)
-	key, ok := pair.Key.(*object.String)
+	tests := []struct {
+		name         string
+		isSort       bool
+		expectedKeys []string
+	}{
+		{"通常は挿入順に列挙する", false, []string{"c", "a", "b"}},
+		{"HASH_SORTの場合はキーの昇順に列挙する", true, []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ht := object.NewHashTable(tt.isSort, false)
+			ht.Set(&object.String{Value: "c"}, &object.Integer{Value: 3})
+			ht.Set(&object.String{Value: "a"}, &object.Integer{Value: 1})
+			ht.Set(&object.String{Value: "b"}, &object.Integer{Value: 2})
+
+			for i, expected := range tt.expectedKeys {
+				pair, ok := ht.GetPairByIndex(i)
+				if !ok {
+					t.Fatalf("pair[%d] is not found", i)
+				}
+				key, ok := pair.Key.(*object.String)
+				if !ok {
+					t.Fatalf("pair.Key is not *object.String. got=%T", pair.Key)
+				}
+				if key.Value != expected {
+					t.Errorf("pair[%d].Key is not %q. got=%q", i, expected, key.Value)
+				}
+			}
+
+			if _, ok := ht.GetPairByIndex(len(tt.expectedKeys)); ok {
+				t.Errorf("out of range index should not be found")
+			}
+		})
+	}
+}
+
+func TestHashTableRemoveKeepsOrder(t *testing.T) {
+	ht := object.NewHashTable(true, false)
+	for _, k := range []int64{30, 10, 20, 40} {
+		ht.Set(&object.Integer{Value: k}, &object.Integer{Value: k})
+	}
+	ht.Remove(&object.Integer{Value: 20})
+
+	expected := "HASH_SORT, 10, 10, 30, 30, 40, 40"
+	if ht.Inspect() != expected {
+		t.Errorf("ht.Inspect() is wrong. expected=%q, got=%q", expected, ht.Inspect())
+	}
+}
+
+func TestEnvironmentCaseInsensitive(t *testing.T) {
+	env := object.NewEnvironment()
+	env.Set("Foo", &object.Integer{Value: 1})
+	env.Set("FOO", &object.Integer{Value: 2})
+
+	val, ok := env.Get("foo")
+	if !ok {
+		t.Fatalf("foo is not found")
+	}
+	if val.(*object.Integer).Value != 2 {
+		t.Errorf("foo has wrong value. got=%s", val.Inspect())
+	}
+
+	binded, ok := object.NewEnclosedEnvironment(env).BindedObject("fOO")
 	if !ok {
-		t.Fatalf("pair.Key is not *object.String. got=%T", pair.Key)
+		t.Fatalf("fOO is not found in enclosed environment")
+	}
+	if binded.Key != "Foo" {
+		t.Errorf("binded.Key is not original spelling. got=%s", binded.Key)
+	}
+}
+
+func TestEnclosedEnvironmentSharesRuntime(t *testing.T) {
+	env := object.NewEnvironment()
+	inner := object.NewEnclosedEnvironment(object.NewEnclosedEnvironment(env))
+
+	// NOTE: 内側の環境を作った後に設定しても反映される
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env.SetContext(ctx)
+	fixed := time.Date(2021, 3, 4, 5, 6, 7, 0, time.Local)
+	env.SetClock(func() time.Time { return fixed })
+	inner.SetOption("EXPLICIT", true)
+
+	if inner.Context() != ctx {
+		t.Errorf("inner context is not shared")
+	}
+	if !inner.Now().Equal(fixed) {
+		t.Errorf("inner clock is not shared. got=%s", inner.Now())
 	}
-	if key.Value != "b" {
-		t.Errorf("key.Value is not 'b'. got=%s", key.Value)
+	if !env.Option("explicit") {
+		t.Errorf("option set in inner environment is not shared")
+	}
+
+	env.SeedRandom(1)
+	expected := object.NewEnvironment()
+	expected.SeedRandom(1)
+	if inner.Random().Int63() != expected.Random().Int63() {
+		t.Errorf("inner random is not shared")
+	}
+}
+
+func TestHashTableCasecare(t *testing.T) {
+	tests := []struct {
+		name        string
+		isCasecare  bool
+		expectedLen int
+	}{
+		{"大文字小文字を区別しない", false, 1},
+		{"大文字小文字を区別する", true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ht := object.NewHashTable(false, tt.isCasecare)
+			ht.Set(&object.String{Value: "key"}, &object.Integer{Value: 1})
+			ht.Set(&object.String{Value: "KEY"}, &object.Integer{Value: 2})
+
+			if ht.Len() != tt.expectedLen {
+				t.Errorf("ht.Len() is wrong. expected=%d, got=%d", tt.expectedLen, ht.Len())
+			}
+			_, ok := ht.Get(&object.String{Value: "Key"})
+			if ok == tt.isCasecare {
+				t.Errorf("ht.Get(\"Key\") is wrong. got=%t", ok)
+			}
+		})
 	}
 }
@@ -12,6 +12,8 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	LOGICAL_OR  // OR または XOR
+	LOGICAL_AND // AND
 	EQUALS      // = または <>
 	LESSGREATER // > または <
 	SUM         // +
@@ -22,6 +24,9 @@ const (
 )
 
 var precedences = map[token.TokenType]int{
+	token.OR:                    LOGICAL_OR,
+	token.XOR:                   LOGICAL_OR,
+	token.AND:                   LOGICAL_AND,
 	token.EQUAL_OR_ASSIGN:       EQUALS,
 	token.NOT_EQUAL:             EQUALS,
 	token.LESS_THAN:             LESSGREATER,
@@ -35,6 +40,7 @@ var precedences = map[token.TokenType]int{
 	token.MOD:                   PRODUCT,
 	token.LEFT_PARENTHESIS:      CALL,
 	token.LEFT_SQUARE_BRACKET:   INDEX,
+	token.DOT:                   INDEX,
 }
 
 type (
@@ -68,8 +74,12 @@ func NewParser(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
 	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
 	p.registerInfix(token.MOD, p.parseInfixExpression)
+	p.registerInfix(token.AND, p.parseInfixExpression)
+	p.registerInfix(token.OR, p.parseInfixExpression)
+	p.registerInfix(token.XOR, p.parseInfixExpression)
 	p.registerInfix(token.LEFT_PARENTHESIS, p.parseCallExpression)
 	p.registerInfix(token.LEFT_SQUARE_BRACKET, p.parseIndexExpression)
+	p.registerInfix(token.DOT, p.parseDotExpression)
 
 	// 2つのトークンを読み込むことでcurTokenとpeekTokenがセットされる
 	p.nextToken()
@@ -115,6 +125,10 @@ func (p *Parser) parseStatement() ast.Statement {
 		return p.parseDimStatement()
 	case token.CONST:
 		return p.parseConstStatement()
+	case token.PUBLIC:
+		return p.parsePublicStatement()
+	case token.OPTION:
+		return p.parseOptionStatement()
 	case token.HASHTBL:
 		return p.parseHashTableStatement()
 	case token.IF:
@@ -201,6 +215,55 @@ func (p *Parser) parseConstStatement() *ast.ConstStatement {
 	return stmt
 }
 
+func (p *Parser) parsePublicStatement() *ast.PublicStatement {
+	stmt := &ast.PublicStatement{Token: p.curToken}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	// 配列の場合
+	if p.peekTokenIs(token.LEFT_SQUARE_BRACKET) {
+		p.nextToken()
+		stmt.Value = p.parseArrayLiteral()
+	} else {
+		if !p.peekTokenIs(token.EQUAL_OR_ASSIGN) {
+			stmt.Value = &ast.Empty{}
+			if p.peekTokenIs(token.EOL) {
+				p.nextToken()
+			}
+			return stmt
+		}
+		if !p.expectPeek(token.EQUAL_OR_ASSIGN) {
+			return nil
+		}
+
+		p.nextToken()
+
+		stmt.Value = p.parseExpression(LOWEST, false)
+
+		if p.peekTokenIs(token.EOL) {
+			p.nextToken()
+		}
+	}
+	return stmt
+}
+
+func (p *Parser) parseOptionStatement() *ast.OptionStatement {
+	stmt := &ast.OptionStatement{Token: p.curToken}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.EOL) {
+		p.nextToken()
+	}
+	return stmt
+}
+
 func (p *Parser) parseHashTableStatement() *ast.HashTableStatement {
 	stmt := &ast.HashTableStatement{Token: p.curToken}
 	if !p.expectPeek(token.IDENT) {
@@ -223,7 +286,20 @@ func (p *Parser) parseHashTableStatement() *ast.HashTableStatement {
 
 	p.nextToken()
 
-	stmt.Value = p.parseExpression(LOWEST, false)
+	// NOTE: 要素が奇数個の場合は先頭をオプションとし、残りをキーと値の組として扱う
+	list := p.parseExpressionList()
+	if list == nil {
+		return nil
+	}
+	stmt.Value = &ast.Empty{}
+	if len(list)%2 == 1 {
+		stmt.Value = list[0]
+		list = list[1:]
+	}
+	for i := 0; i < len(list); i += 2 {
+		stmt.Keys = append(stmt.Keys, list[i])
+		stmt.Values = append(stmt.Values, list[i+1])
+	}
 
 	if p.peekTokenIs(token.EOL) {
 		p.nextToken()
@@ -289,6 +365,10 @@ func (p *Parser) parseExpression(precedure int, isStartOfLine bool) ast.Expressi
 		leftExp = p.parsePrefixExpression()
 	case token.TRUE, token.FALSE:
 		leftExp = p.parseBoolean()
+	case token.EMPTY:
+		leftExp = &ast.Empty{Token: p.curToken}
+	case token.NULL:
+		leftExp = &ast.Null{Token: p.curToken}
 	case token.LEFT_PARENTHESIS:
 		leftExp = p.parseGroupedExpression()
 	case token.STRING:
@@ -586,19 +666,18 @@ func (p *Parser) parseCallArguments() []ast.Expression {
 		return args
 	}
 
-	p.nextToken()
-
-	args = append(args, p.parseExpression(LOWEST, false))
-
-	for p.peekTokenIs(token.COMMA) {
-		p.nextToken()
-		// カンマが連続
-		if p.peekTokenIs(token.COMMA) {
+	for {
+		// 省略された引数(先頭、カンマの連続、末尾)
+		if p.peekTokenIs(token.COMMA) || p.peekTokenIs(token.RIGHT_PARENTHESIS) {
 			args = append(args, &ast.EmptyArgument{})
+		} else {
 			p.nextToken()
+			args = append(args, p.parseExpression(LOWEST, false))
+		}
+		if !p.peekTokenIs(token.COMMA) {
+			break
 		}
 		p.nextToken()
-		args = append(args, p.parseExpression(LOWEST, false))
 	}
 
 	if !p.expectPeek(token.RIGHT_PARENTHESIS) {
@@ -705,6 +784,21 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	return exp
 }
 
+// hash.key のようにメンバーを参照する
+func (p *Parser) parseDotExpression(left ast.Expression) ast.Expression {
+	exp := &ast.DotExpression{
+		Token: p.curToken,
+		Left:  left,
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	exp.Member = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	return exp
+}
+
 func (p *Parser) parseForStatement() ast.Statement {
 	tok := p.curToken
 
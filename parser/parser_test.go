@@ -145,6 +145,72 @@ func testConstStatement(t *testing.T, s ast.Statement, name string) bool {
 	return true
 }
 
+func TestPublicStatements(t *testing.T) {
+	tests := []struct {
+		name               string
+		input              string
+		expectedIdentifier string
+		expectedValue      interface{}
+	}{
+		{
+			"整数のグローバル変数宣言",
+			"PUBLIC val = 5",
+			"val",
+			5,
+		},
+		{
+			"値のないグローバル変数宣言",
+			"PUBLIC val",
+			"val",
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := lexer.NewLexer(tt.input)
+			p := parser.NewParser(l)
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			if len(program.Statements) != 1 {
+				t.Fatalf("program.Statements does not contain 1 statements. got=%d", len(program.Statements))
+			}
+
+			stmt, ok := program.Statements[0].(*ast.PublicStatement)
+			if !ok {
+				t.Fatalf("stmt not *ast.PublicStatement. got=%T", program.Statements[0])
+			}
+			if stmt.Name.Value != tt.expectedIdentifier {
+				t.Errorf("stmt.Name.Value not '%s'. got=%s", tt.expectedIdentifier, stmt.Name.Value)
+			}
+
+			if tt.expectedValue == nil {
+				if _, ok := stmt.Value.(*ast.Empty); !ok {
+					t.Errorf("stmt.Value not *ast.Empty. got=%T", stmt.Value)
+				}
+				return
+			}
+			testLiteralExpression(t, stmt.Value, tt.expectedValue)
+		})
+	}
+}
+
+func TestOptionStatement(t *testing.T) {
+	l := lexer.NewLexer("OPTION EXPLICIT")
+	p := parser.NewParser(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.OptionStatement)
+	if !ok {
+		t.Fatalf("stmt not *ast.OptionStatement. got=%T", program.Statements[0])
+	}
+	if stmt.Name.Value != "EXPLICIT" {
+		t.Errorf("stmt.Name.Value not 'EXPLICIT'. got=%s", stmt.Name.Value)
+	}
+}
+
 func checkParserErrors(t *testing.T, p *parser.Parser) {
 	errors := p.Errors()
 	if len(errors) == 0 {
@@ -641,6 +707,26 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * xxx[1])",
 			"add((a * (b[2])), (b[1]), (2 * (xxx[1])))",
 		},
+		{
+			"論理演算子は比較演算子より優先度が低い",
+			"a < b OR c = d AND e",
+			"((a < b) OR ((c = d) AND e))",
+		},
+		{
+			"ORとXORは同じ優先度",
+			"a OR b XOR c",
+			"((a OR b) XOR c)",
+		},
+		{
+			"EMPTYとNULLを値として比較できる",
+			"EMPTY = a OR NULL <> b",
+			"((EMPTY = a) OR (NULL <> b))",
+		},
+		{
+			"メンバーの参照は添字と同じ優先順位",
+			"a.b[1].c + d",
+			"((((a.b)[1]).c) + d)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -974,6 +1060,44 @@ func TestCallExpressionEmptyParsing(t *testing.T) {
 	testLiteralExpression(t, exp.Arguments[2], 3)
 }
 
+func TestCallExpressionEmptyParsing_省略位置(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []bool // 引数が省略されているか
+	}{
+		{"途中の引数を省略", "fn(1, , 3)", []bool{false, true, false}},
+		{"先頭の引数を省略", "fn(, 2)", []bool{true, false}},
+		{"先頭から連続して省略", "fn(, , 3)", []bool{true, true, false}},
+		{"カンマが3つ以上連続", "fn(1, , , 4)", []bool{false, true, true, false}},
+		{"末尾の引数を省略", "fn(1, )", []bool{false, true}},
+		{"すべての引数を省略", "fn(, )", []bool{true, true}},
+		{"引数なし", "fn()", []bool{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := lexer.NewLexer(tt.input)
+			p := parser.NewParser(l)
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			exp, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+			if !ok {
+				t.Fatalf("stmt.Expression is not ast.CallExpression. got=%T", program.Statements[0])
+			}
+			if len(exp.Arguments) != len(tt.expected) {
+				t.Fatalf("wrong length of arguments. got=%d", len(exp.Arguments))
+			}
+			for i, empty := range tt.expected {
+				if _, ok := exp.Arguments[i].(*ast.EmptyArgument); ok != empty {
+					t.Errorf("exp.Arguments[%d] is wrong. got=%T", i, exp.Arguments[i])
+				}
+			}
+		})
+	}
+}
+
 func TestResultStatements(t *testing.T) {
 	input := `FUNCTION fn()
 	RESULT = 5
@@ -1256,6 +1380,34 @@ func TestHashTableStatement(t *testing.T) {
 	}
 }
 
+func TestHashTableStatementWithPairs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"キーと値の組で初期化する", `HASHTBL val = "one", 1, "two", 2 * 3`, "HASHTBL val = one, 1, two, (2 * 3)"},
+		{"オプションとキーと値の組で初期化する", `HASHTBL val = HASH_SORT, "one", 1`, "HASHTBL val = HASH_SORT, one, 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := lexer.NewLexer(tt.input)
+			p := parser.NewParser(l)
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			stmt, ok := program.Statements[0].(*ast.HashTableStatement)
+			if !ok {
+				t.Fatalf("stmt not ast.HashTableStatement. got=%T", program.Statements[0])
+			}
+			if stmt.String() != tt.expected {
+				t.Errorf("stmt.String() wrong. expected=%q, got=%q", tt.expected, stmt.String())
+			}
+		})
+	}
+}
+
 func TestFORTONEXTStatement(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -21,6 +21,9 @@ func Start(in io.Reader, out io.Writer) {
 		fmt.Print(PROMPT)
 		scanned := scanner.Scan()
 		if !scanned {
+			if err := evaluator.CloseFiles(env); err != nil {
+				io.WriteString(out, "ERROR: cannot write file: "+err.Error()+"\n")
+			}
 			return
 		}
 
@@ -34,7 +37,7 @@ func Start(in io.Reader, out io.Writer) {
 			continue
 		}
 
-		evaluated := evaluator.Eval(program, env)
+		evaluated := evaluator.EvalSafely(program, env)
 		if evaluated != nil {
 			io.WriteString(out, evaluated.Inspect())
 			io.WriteString(out, "\n")
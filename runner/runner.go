@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"context"
+	"io"
+
+	"github.com/sam8helloworld/uwscgo/evaluator"
+	"github.com/sam8helloworld/uwscgo/lexer"
+	"github.com/sam8helloworld/uwscgo/object"
+	"github.com/sam8helloworld/uwscgo/parser"
+)
+
+// スクリプト全体を評価し、エラーがなければtrueを返す
+// NOTE: 構文エラーと実行時エラーはerrOut(通常は標準エラー出力)に書き込む
+func Run(in io.Reader, errOut io.Writer) bool {
+	return RunContext(context.Background(), in, errOut)
+}
+
+// ctxがキャンセルされた場合はSLEEPの待機中でもスクリプトを中断する
+func RunContext(ctx context.Context, in io.Reader, errOut io.Writer) bool {
+	input, err := io.ReadAll(in)
+	if err != nil {
+		io.WriteString(errOut, "ERROR: "+err.Error()+"\n")
+		return false
+	}
+
+	l := lexer.NewLexer(string(input))
+	p := parser.NewParser(l)
+
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		for _, msg := range p.Errors() {
+			io.WriteString(errOut, "\t"+msg+"\n")
+		}
+		return false
+	}
+
+	env := object.NewEnvironment()
+	env.SetContext(ctx)
+	evaluated := evaluator.EvalSafely(program, env)
+	ok := true
+	if errObj, isErr := evaluated.(*object.Error); isErr {
+		io.WriteString(errOut, errObj.Inspect())
+		io.WriteString(errOut, "\n")
+		ok = false
+	}
+	// NOTE: エラーで終了した場合もFCLOSEしていないファイルは書き込む
+	if err := evaluator.CloseFiles(env); err != nil {
+		io.WriteString(errOut, "ERROR: cannot write file: "+err.Error()+"\n")
+		ok = false
+	}
+	return ok
+}
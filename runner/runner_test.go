@@ -0,0 +1,92 @@
+package runner_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sam8helloworld/uwscgo/runner"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedOk     bool
+		expectedErrOut string
+	}{
+		{
+			"エラーがなければ何も出力しない",
+			`DIM val = 1 + 1`,
+			true,
+			"",
+		},
+		{
+			"実行時エラーはスタックトレースと共に出力する",
+			`DIM val = 1
+val = val / 0`,
+			false,
+			"ERROR: division by zero: 1 / 0\n\tat <main> (line 2)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errOut bytes.Buffer
+			ok := runner.Run(strings.NewReader(tt.input), &errOut)
+			if ok != tt.expectedOk {
+				t.Errorf("wrong result. expected=%t, got=%t", tt.expectedOk, ok)
+			}
+			if errOut.String() != tt.expectedErrOut {
+				t.Errorf("wrong error output. expected=%q, got=%q", tt.expectedErrOut, errOut.String())
+			}
+		})
+	}
+}
+
+func TestRunContext_キャンセルでSLEEPを中断する(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var errOut bytes.Buffer
+	start := time.Now()
+	ok := runner.RunContext(ctx, strings.NewReader("SLEEP(10)\nDIM val = 1"), &errOut)
+	if ok {
+		t.Fatalf("RunContext should fail when cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("SLEEP was not interrupted. elapsed=%s", elapsed)
+	}
+	expected := "ERROR: script interrupted: context deadline exceeded\n\tat <main> (line 1)\n"
+	if errOut.String() != expected {
+		t.Errorf("wrong error output. expected=%q, got=%q", expected, errOut.String())
+	}
+}
+
+func TestRun_FCLOSEしていないファイルは終了時に書き込む(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.txt")
+	input := `f = FOPEN("` + path + `", F_WRITE)
+FPUT(f, "a")
+g = FOPEN("` + path + `.discard", F_WRITE)
+FPUT(g, "b")
+FCLOSE(g, TRUE)`
+
+	var errOut bytes.Buffer
+	if !runner.Run(strings.NewReader(input), &errOut) {
+		t.Fatalf("Run failed. output=%q", errOut.String())
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "a\r\n" {
+		t.Errorf("file has wrong content. expected=%q, got=%q", "a\r\n", string(b))
+	}
+	if _, err := os.Stat(path + ".discard"); !os.IsNotExist(err) {
+		t.Errorf("discarded file should not be written. err=%v", err)
+	}
+}
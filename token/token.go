@@ -6,6 +6,7 @@ type TokenType string
 type Token struct {
 	Type    TokenType
 	Literal string
+	Line    int // 1始まりの行番号
 }
 
 const (
@@ -32,8 +33,12 @@ const (
 
 	HASHTBL = "HASHTBL"
 
+	OPTION = "OPTION"
+
 	TRUE  = "TRUE"
 	FALSE = "FALSE"
+	EMPTY = "EMPTY"
+	NULL  = "NULL"
 
 	EQUAL_OR_ASSIGN       = "="
 	NOT_EQUAL             = "<>"
@@ -46,6 +51,9 @@ const (
 	ASTERISK              = "*"
 	SLASH                 = "/"
 	MOD                   = "MOD"
+	AND                   = "AND"
+	OR                    = "OR"
+	XOR                   = "XOR"
 	BANG                  = "!"
 	LEFT_PARENTHESIS      = "("
 	RIGHT_PARENTHESIS     = ")"
@@ -54,6 +62,7 @@ const (
 	LEFT_BRACKET          = "{"
 	RIGHT_BRACKET         = "}"
 	COMMA                 = ","
+	DOT                   = "."
 
 	IF     = "IF"
 	ELSEIF = "ELSEIF"
@@ -89,9 +98,15 @@ var reservedWords = map[string]TokenType{
 	"PUBLIC":    PUBLIC,
 	"CONST":     CONST,
 	"HASHTBL":   HASHTBL,
+	"OPTION":    OPTION,
 	"TRUE":      TRUE,
 	"FALSE":     FALSE,
+	"EMPTY":     EMPTY,
+	"NULL":      NULL,
 	"MOD":       MOD,
+	"AND":       AND,
+	"OR":        OR,
+	"XOR":       XOR,
 	"IF":        IF,
 	"ELSEIF":    ELSEIF,
 	"ELSE":      ELSE,